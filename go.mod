@@ -11,6 +11,7 @@ require (
 	github.com/rvflash/workr v1.0.0
 	go.uber.org/mock v0.5.2
 	golang.org/x/mod v0.25.0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
@@ -7,13 +7,21 @@ package app
 
 import (
 	"context"
+	stderrors "errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"strconv"
+	"sync"
+	"sync/atomic"
 
+	"github.com/rvflash/goup/internal/config"
+	"github.com/rvflash/goup/internal/diff"
 	"github.com/rvflash/goup/internal/errors"
 	"github.com/rvflash/goup/internal/log"
 	"github.com/rvflash/goup/internal/netrc"
+	"github.com/rvflash/goup/internal/report"
 	"github.com/rvflash/goup/internal/vcs"
 	"github.com/rvflash/goup/pkg/goup"
 	"github.com/rvflash/goup/pkg/mod"
@@ -34,6 +42,18 @@ func WithChecker(f goup.Checker) Configurator {
 	}
 }
 
+// WithFlags defines the function applying the flags explicitly set by the user.
+// They override the settings of the configuration file.
+func WithFlags(f func(c *goup.Config) error) Configurator {
+	return func(a *App) error {
+		if f == nil {
+			return errors.NewMissingData("flags")
+		}
+		a.flags = f
+		return nil
+	}
+}
+
 // WithLogger defines the logger used to print events.
 // By default, we use a DevNull.
 func WithLogger(l log.Printer) Configurator {
@@ -46,6 +66,18 @@ func WithLogger(l log.Printer) Configurator {
 	}
 }
 
+// WithOutput defines the writer used to print the report.
+// By default, we use a io.Discard.
+func WithOutput(w io.Writer) Configurator {
+	return func(a *App) error {
+		if w == nil {
+			return errors.NewMissingData("report")
+		}
+		a.output = w
+		return nil
+	}
+}
+
 // WithParser defines the go module parser to use.
 // By default, the Parse method from the internal parse package.
 func WithParser(f mod.Parser) Configurator {
@@ -58,6 +90,18 @@ func WithParser(f mod.Parser) Configurator {
 	}
 }
 
+// WithWorkParser defines the go workspace parser to use.
+// By default, the ParseWork method from the mod package.
+func WithWorkParser(f mod.WorkParser) Configurator {
+	return func(a *App) error {
+		if f == nil {
+			return errors.NewMissingData("work parser")
+		}
+		a.parseWork = f
+		return nil
+	}
+}
+
 // WithNetrc parses netrc file to allow auto-login with basic authentication.
 func WithNetrc() Configurator {
 	return func(a *App) error {
@@ -77,9 +121,11 @@ func Open(version string, opts ...Configurator) (*App, error) {
 	}
 	opts = append([]Configurator{
 		WithLogger(log.DevNull()),
+		WithOutput(io.Discard),
 		WithNetrc(),
 		WithParser(mod.Parse),
-		WithChecker(goup.Check),
+		WithWorkParser(mod.ParseWork),
+		WithChecker(goup.NewChecker()),
 	}, opts...)
 	for _, opt := range opts {
 		err := opt(a)
@@ -95,9 +141,12 @@ type App struct {
 	goup.Config
 
 	check        goup.Checker
+	flags        func(c *goup.Config) error
 	autologin    vcs.BasicAuthentifier
 	parse        mod.Parser
+	parseWork    mod.WorkParser
 	logger       log.Printer
+	output       io.Writer
 	buildVersion string
 }
 
@@ -117,24 +166,235 @@ func (a *App) Check(ctx context.Context, paths []string) (failure bool) {
 			return false
 		}
 	}
-	a.Config.BasicAuth = a.autologin
-	for _, path := range checkPaths(paths) {
-		f, err := a.parse(path)
+	write, err := report.Lookup(a.Format)
+	if err != nil {
+		a.logger.Errorf(err.Error())
+		return true
+	}
+	switch a.Align {
+	case "", goup.AlignHighest, goup.AlignLatest:
+	default:
+		a.logger.Errorf("%s: unknown alignment: %q", errors.ErrConfig.Error(), a.Align)
+		return true
+	}
+	rep := report.New(a.buildVersion)
+	if write != nil {
+		defer func() {
+			if err := write(a.output, rep); err != nil {
+				a.logger.Errorf(err.Error())
+				failure = true
+			}
+		}()
+	}
+	var (
+		files   = checkPaths(paths)
+		checked = make(map[string]bool)
+		parsed  = make(map[string]mod.Mod)
+		results []*result
+		works   []workspace
+		halt    func() bool
+	)
+	for i := 0; i < len(files); i++ {
+		path := files[i]
+		if checked[filepath.Clean(path)] {
+			// Already checked, as a module of a workspace for example.
+			continue
+		}
+		checked[filepath.Clean(path)] = true
+		conf, err := a.config(path)
 		if err != nil {
-			a.logger.Errorf(err.Error())
+			halt = a.fail(rep, path, err)
+			break
+		}
+		if conf.PrintConfig {
+			if err = a.printConfig(path, conf); err != nil {
+				a.logger.Errorf(err.Error())
+				return true
+			}
+			continue
+		}
+		f, err := a.open(path)
+		if err != nil {
+			halt = a.fail(rep, path, err)
+			break
+		}
+		res := &result{path: path, file: f, conf: conf, index: i, done: make(chan struct{})}
+		results = append(results, res)
+		if w, ok := f.(*mod.Work); ok {
+			// The modules used by the workspace are checked right after it.
+			files = append(files[:i+1], append(w.Uses(), files[i+1:]...)...)
+			works = append(works, workspace{work: w, result: res})
+		} else {
+			parsed[filepath.Clean(path)] = f
+		}
+	}
+	stop := a.checkAll(ctx, results)
+	defer stop()
+	for i, res := range results {
+		<-res.done
+		if res.aborted {
+			a.abort(rep, pending(files[res.index:], results[:i]))
 			return true
 		}
-		for msg := range a.check(ctx, f, a.Config) {
-			switch msg.Level() {
-			case goup.DebugLevel:
-				a.logger.Debugf(msg.Format(), msg.Args()...)
-			case goup.InfoLevel:
-				a.logger.Infof(msg.Format(), msg.Args()...)
-			case goup.WarnLevel:
-				a.logger.Warnf(msg.Format(), msg.Args()...)
+		loc, _ := res.file.(report.Locator)
+		res.report = rep.AddFile(res.path, res.file.Module(), loc)
+		for _, msg := range res.messages {
+			res.report.Add(msg)
+			if a.print(msg) {
+				failure = true
+			}
+		}
+		if res.errored && res.conf.Strict {
+			stop()
+			a.diffAll(results[:i+1])
+			a.abort(rep, pending(files[res.index+1:], results[:i+1]))
+			return true
+		}
+	}
+	if halt != nil {
+		// The files following the one in error are not checked.
+		return halt()
+	}
+	for _, ws := range works {
+		var members []mod.Mod
+		for _, path := range ws.work.Uses() {
+			if m, ok := parsed[filepath.Clean(path)]; ok {
+				members = append(members, m)
+			}
+		}
+		for _, msg := range goup.Drift(members) {
+			ws.result.report.Add(msg)
+			if a.print(msg) {
+				failure = true
+			}
+		}
+	}
+	if a.Align != "" && a.align(ctx, results) {
+		failure = true
+	}
+	if a.diffAll(results) {
+		failure = true
+	}
+	return failure
+}
+
+// result is the check of a go.mod or go.work file.
+// Its index is its position in the list of files to check.
+// Its messages, errored and aborted properties are only set once done is closed.
+type result struct {
+	path     string
+	file     mod.Mod
+	conf     goup.Config
+	report   *report.File
+	messages []goup.Message
+	errored  bool
+	aborted  bool
+	index    int
+	done     chan struct{}
+}
+
+// checkAll checks the files of these results concurrently, with at most Jobs files at the same time.
+// In strict mode, the files are checked one by one and the first one in error aborts the following ones.
+// The returned function stops the checks in progress and waits for them.
+func (a *App) checkAll(parent context.Context, results []*result) (stop func()) {
+	var (
+		ctx, cancel = context.WithCancel(parent)
+		queue       = make(chan *result, len(results))
+		jobs        = a.Jobs
+		stopped     int32
+		wg          sync.WaitGroup
+		once        sync.Once
+	)
+	for _, r := range results {
+		queue <- r
+		if r.conf.Strict {
+			jobs = 1
+		}
+	}
+	close(queue)
+	if jobs < 1 {
+		jobs = 1
+	}
+	for n := 0; n < jobs; n++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for r := range queue {
+				if atomic.LoadInt32(&stopped) > 0 {
+					r.aborted = true
+					close(r.done)
+					continue
+				}
+				for msg := range a.check(ctx, r.file, r.conf) {
+					r.messages = append(r.messages, msg)
+					r.errored = r.errored || msg.Level() == goup.ErrorLevel
+				}
+				if r.errored && r.conf.Strict {
+					atomic.StoreInt32(&stopped, 1)
+				}
+				close(r.done)
+			}
+		}()
+	}
+	return func() {
+		once.Do(func() {
+			atomic.StoreInt32(&stopped, 1)
+			cancel()
+			wg.Wait()
+		})
+	}
+}
+
+// fail reports the go.mod file which can not be checked.
+// The returned function is used to notify it after the checks of the previous files.
+func (a *App) fail(rep *report.Report, path string, err error) func() bool {
+	return func() bool {
+		a.logger.Errorf(err.Error())
+		rep.AddFile(path, "", nil).AddError(err.Error())
+		return true
+	}
+}
+
+func (r *result) add(msg goup.Message) {
+	r.report.Add(msg)
+	r.messages = append(r.messages, msg)
+}
+
+// align aligns the dependencies required at different versions by the go.mod files checked without error.
+// Once a go.mod file written, its go.sum file is updated as with the check.
+// It returns true if a dependency is misaligned or can not be aligned.
+func (a *App) align(ctx context.Context, results []*result) (failure bool) {
+	al := goup.NewAligner(a.Align)
+	for _, r := range results {
+		if _, ok := r.file.(*mod.Work); !ok && !r.errored {
+			al.Add(r.file, r.messages)
+		}
+	}
+	for _, r := range results {
+		var (
+			update  = r.conf.ForceUpdate || r.conf.Diff
+			updated bool
+		)
+		for _, msg := range al.Align(r.file, update) {
+			r.add(msg)
+			if a.print(msg) {
 				failure = true
-			default:
-				a.logger.Errorf(msg.Format(), msg.Args()...)
+				r.errored = r.errored || msg.Level() == goup.ErrorLevel
+			}
+			updated = updated || msg.Status() == goup.Updated
+		}
+		if !updated || r.errored || r.conf.Diff {
+			continue
+		}
+		// The check has already written its own updates, those of the alignment are added.
+		if err := write(r.file); err != nil {
+			a.logger.Errorf("%s: %s", r.path, err.Error())
+			failure = true
+			continue
+		}
+		for msg := range goup.UpdateSum(ctx, r.file, r.conf) {
+			r.add(msg)
+			if a.print(msg) {
 				failure = true
 			}
 		}
@@ -142,18 +402,167 @@ func (a *App) Check(ctx context.Context, paths []string) (failure bool) {
 	return failure
 }
 
+// diffAll prints the changes of the go.mod files checked in diff mode.
+// It returns true if one of them failed.
+func (a *App) diffAll(results []*result) (failure bool) {
+	for _, r := range results {
+		if !r.conf.Diff || r.errored {
+			continue
+		}
+		if err := a.diff(r.path, r.file); err != nil {
+			a.logger.Errorf(err.Error())
+			failure = true
+		}
+	}
+	return failure
+}
+
+// open parses the go.mod or go.work file behind this path.
+func (a *App) open(path string) (mod.Mod, error) {
+	if filepath.Base(path) == mod.WorkFilename {
+		w, err := a.parseWork(path)
+		if err != nil {
+			return nil, err
+		}
+		return w, nil
+	}
+	f, err := a.parse(path)
+	if err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// workspace is a go.work file with its result.
+type workspace struct {
+	work   *mod.Work
+	result *result
+}
+
+// print logs the message regarding its level and returns true if it is a warning or an error.
+func (a *App) print(msg goup.Message) bool {
+	switch msg.Level() {
+	case goup.DebugLevel:
+		a.logger.Debugf(msg.Format(), msg.Args()...)
+	case goup.InfoLevel:
+		a.logger.Infof(msg.Format(), msg.Args()...)
+	case goup.WarnLevel:
+		a.logger.Warnf(msg.Format(), msg.Args()...)
+		return true
+	default:
+		a.logger.Errorf(msg.Format(), msg.Args()...)
+		return true
+	}
+	return false
+}
+
+// pending returns the paths of these files not reported yet, without duplicate.
+// The modules of a workspace are listed after it, so they may already be listed or reported.
+func pending(files []string, reported []*result) []string {
+	seen := make(map[string]bool, len(reported))
+	for _, r := range reported {
+		seen[filepath.Clean(r.path)] = true
+	}
+	var res []string
+	for _, path := range files {
+		if p := filepath.Clean(path); !seen[p] {
+			seen[p] = true
+			res = append(res, path)
+		}
+	}
+	return res
+}
+
+// abort notifies the files not checked due to the strict mode.
+func (a *App) abort(rep *report.Report, paths []string) {
+	if len(paths) == 0 {
+		return
+	}
+	for _, path := range paths {
+		rep.AddFile(path, "", nil).Aborted = true
+		a.logger.Debugf("%s: check aborted", path)
+	}
+	a.logger.Infof("strict mode: %s go.mod file(s) not checked", strconv.Itoa(len(paths)))
+}
+
+// config returns the settings to use to check this go.mod file.
+// The configuration file found from the go.mod directory upward overrides the default settings
+// and the flags explicitly set override both of them.
+func (a *App) config(path string) (goup.Config, error) {
+	conf := a.Config
+	conf.BasicAuth = a.autologin
+	name, err := config.Find(filepath.Dir(path))
+	if err != nil {
+		return conf, err
+	}
+	if name != "" {
+		f, err := config.Load(name)
+		if err != nil {
+			return conf, err
+		}
+		f.Apply(&conf)
+	}
+	if a.flags != nil {
+		err = a.flags(&conf)
+	}
+	return conf, err
+}
+
+// diff prints the changes applied in memory on this go.mod file as a unified diff.
+func (a *App) diff(path string, f mod.Mod) error {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	buf, err := f.Format()
+	if err != nil {
+		if stderrors.Is(err, errors.ErrNotModified) {
+			return nil
+		}
+		return err
+	}
+	name := filepath.ToSlash(filepath.Clean(path))
+	_, err = a.output.Write(diff.Unified("a/"+name, "b/"+name, b, buf))
+	return err
+}
+
+func write(f mod.Mod) error {
+	buf, err := f.Format()
+	if err != nil {
+		if stderrors.Is(err, errors.ErrNotModified) {
+			return nil
+		}
+		return err
+	}
+	return os.WriteFile(f.Name(), buf, perm)
+}
+
+func (a *App) printConfig(path string, conf goup.Config) error {
+	_, err := fmt.Fprintf(a.output, "# %s\n", path)
+	if err != nil {
+		return err
+	}
+	return config.New(conf).Write(a.output)
+}
+
 func (a *App) ready(ctx context.Context) bool {
-	return ctx != nil && a.check != nil && a.parse != nil && a.logger != nil
+	return ctx != nil && a.check != nil && a.parse != nil && a.parseWork != nil && a.logger != nil
 }
 
 const (
+	perm       = 0o644
 	currentDir = "."
 	recursive  = "./..."
 )
 
+// checkPaths returns the go.mod and go.work files behind these paths.
+// Without path, the go.work file of the current directory is preferred to its go.mod file.
 func checkPaths(paths []string) []string {
 	switch len(paths) {
 	case 0:
+		if _, err := os.Stat(filepath.Join(currentDir, mod.WorkFilename)); err == nil {
+			return []string{filepath.Join(currentDir, mod.WorkFilename)}
+		}
 		return []string{filePath(currentDir)}
 	case 1:
 		if paths[0] == recursive {
@@ -167,7 +576,7 @@ func checkPaths(paths []string) []string {
 }
 
 func filePath(path string) string {
-	if filepath.Base(path) == mod.Filename {
+	if name := filepath.Base(path); name == mod.Filename || name == mod.WorkFilename {
 		return path
 	}
 	return filepath.Join(path, mod.Filename)
@@ -179,7 +588,7 @@ func walkPath(root string) []string {
 		if err != nil {
 			return err
 		}
-		if filepath.Base(path) == mod.Filename {
+		if name := filepath.Base(path); name == mod.Filename || name == mod.WorkFilename {
 			res = append(res, path)
 		}
 		return nil
@@ -8,14 +8,18 @@ import (
 	"context"
 	"errors"
 	"io"
+	"os"
 	"path/filepath"
 	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/matryer/is"
 	"github.com/rvflash/goup/internal/app"
 	errup "github.com/rvflash/goup/internal/errors"
 	"github.com/rvflash/goup/internal/log"
+	"github.com/rvflash/goup/internal/sum"
 	"github.com/rvflash/goup/pkg/goup"
 	"github.com/rvflash/goup/pkg/mod"
 )
@@ -96,6 +100,25 @@ func TestOpen(t *testing.T) {
 				config: goup.Config{PrintVersion: true, OnlyReleases: fileOutdated, Verbose: true},
 				stderr: wv + log.Prefix + noop + "\n",
 			},
+			"strict": {
+				ctx:    context.Background(),
+				in:     []string{"a", "b", "c"},
+				out:    true,
+				config: goup.Config{Strict: true, OnlyReleases: fileErr},
+				stderr: log.Prefix + oops + "\n" + log.Prefix + "strict mode: 2 go.mod file(s) not checked\n",
+			},
+			"unknown format": {
+				ctx:    context.Background(),
+				in:     []string{fileOK},
+				out:    true,
+				config: goup.Config{Format: "xml"},
+				stderr: log.Prefix + "format \"xml\": unknown format\n",
+			},
+			"json": {
+				ctx:    context.Background(),
+				in:     []string{fileOK},
+				config: goup.Config{Format: "json", OnlyReleases: "ok"},
+			},
 			"recursive": {
 				ctx:    context.Background(),
 				in:     []string{"./..."},
@@ -147,6 +170,54 @@ func TestWithChecker(t *testing.T) {
 	})
 }
 
+func TestWithFlags(t *testing.T) {
+	t.Parallel()
+	are := is.New(t)
+
+	t.Run("default", func(t *testing.T) {
+		t.Parallel()
+		a, err := app.Open(version, app.WithFlags(nil))
+		are.True(errors.Is(err, errup.ErrMissing)) // mismatch error
+		are.True(a == nil)                         // mismatch result
+	})
+
+	t.Run("override", func(t *testing.T) {
+		t.Parallel()
+		var (
+			c = &checker{}
+			p = &parser{}
+			f = func(c *goup.Config) error {
+				c.OnlyReleases = fileErr
+				return nil
+			}
+		)
+		a, err := app.Open(version, app.WithFlags(f), app.WithChecker(c.Check), app.WithParser(p.Parse))
+		are.NoErr(err) // mismatch error
+		a.Config = goup.Config{OnlyReleases: fileOK}
+		are.True(a.Check(context.Background(), []string{fileOK})) // expected failure
+	})
+
+	t.Run("print config", func(t *testing.T) {
+		t.Parallel()
+		var (
+			dir = t.TempDir()
+			out = new(strings.Builder)
+			f   = func(c *goup.Config) error {
+				c.ExcludeIndirect = true
+				return nil
+			}
+		)
+		err := os.WriteFile(filepath.Join(dir, ".goup.yaml"), []byte("update: minor\nexclude-indirect: false\n"), 0o600)
+		are.NoErr(err) // mismatch error
+		a, err := app.Open(version, app.WithFlags(f), app.WithOutput(out))
+		are.NoErr(err) // mismatch error
+		a.Config = goup.Config{PrintConfig: true}
+		are.True(!a.Check(context.Background(), []string{dir}))            // unexpected failure
+		are.True(strings.Contains(out.String(), "update: minor"))          // mismatch mode
+		are.True(strings.Contains(out.String(), "exclude-indirect: true")) // mismatch flag
+	})
+}
+
 func TestWithLogger(t *testing.T) {
 	t.Parallel()
 	are := is.New(t)
@@ -168,6 +239,64 @@ func TestWithLogger(t *testing.T) {
 	})
 }
 
+func TestWithOutput(t *testing.T) {
+	t.Parallel()
+	are := is.New(t)
+
+	t.Run("default", func(t *testing.T) {
+		t.Parallel()
+		a, err := app.Open(version, app.WithOutput(nil))
+		are.True(errors.Is(err, errup.ErrMissing)) // mismatch error
+		are.True(a == nil)                         // mismatch result
+	})
+
+	t.Run("json", func(t *testing.T) {
+		t.Parallel()
+		var (
+			out = new(strings.Builder)
+			c   = &checker{}
+			p   = &parser{}
+		)
+		a, err := app.Open(version, app.WithOutput(out), app.WithChecker(c.Check), app.WithParser(p.Parse))
+		are.NoErr(err) // mismatch error
+		a.Config = goup.Config{Format: "json", OnlyReleases: fileErr}
+		are.True(a.Check(context.Background(), []string{fileOK}))        // expected failure
+		are.True(strings.Contains(out.String(), `"errors": [`))          // mismatch errors
+		are.True(strings.Contains(out.String(), `"version": "`+version)) // mismatch version
+	})
+
+	t.Run("diff", func(t *testing.T) {
+		t.Parallel()
+		var (
+			dir  = t.TempDir()
+			name = filepath.Join(dir, mod.Filename)
+			out  = new(strings.Builder)
+			c    = func(_ context.Context, f mod.Mod, _ goup.Config) chan goup.Message {
+				ch := make(chan goup.Message)
+				go func() {
+					defer close(ch)
+					if err := f.UpdateRequire("github.com/matryer/is", "v1.4.1"); err != nil {
+						ch <- goup.NewEntry(goup.ErrorLevel, "%s", err)
+					}
+				}()
+				return ch
+			}
+		)
+		b := []byte("module example.com/group/go\n\nrequire github.com/matryer/is v1.2.0\n")
+		are.NoErr(os.WriteFile(name, b, 0o600)) // mismatch error
+		a, err := app.Open(version, app.WithOutput(out), app.WithChecker(c))
+		are.NoErr(err) // mismatch error
+		a.Config = goup.Config{Diff: true}
+		are.True(!a.Check(context.Background(), []string{dir}))                   // unexpected failure
+		are.True(strings.Contains(out.String(), "+++ b/"+filepath.ToSlash(name))) // mismatch file
+		are.True(strings.Contains(out.String(), "-require github.com/matryer/is v1.2.0\n"+
+			"+require github.com/matryer/is v1.4.1\n")) // mismatch diff
+		buf, err := os.ReadFile(name)
+		are.NoErr(err)    // mismatch error
+		are.Equal(buf, b) // file modified
+	})
+}
+
 func TestWithParser(t *testing.T) {
 	t.Parallel()
 	are := is.New(t)
@@ -187,6 +316,149 @@ func TestWithParser(t *testing.T) {
 	})
 }
 
+func TestWithWorkParser(t *testing.T) {
+	t.Parallel()
+	are := is.New(t)
+	a, err := app.Open(version, app.WithWorkParser(nil))
+	are.True(errors.Is(err, errup.ErrMissing)) // mismatch error
+	are.True(a == nil)                         // mismatch result
+}
+
+func TestApp_Check_Workspace(t *testing.T) {
+	t.Parallel()
+	var (
+		are    = is.New(t)
+		calls  int32
+		stderr = new(strings.Builder)
+		work   = filepath.Join("..", "..", "testdata", "golden", "work")
+		c      = func(_ context.Context, _ mod.Mod, _ goup.Config) chan goup.Message {
+			atomic.AddInt32(&calls, 1)
+			ch := make(chan goup.Message)
+			close(ch)
+			return ch
+		}
+	)
+	a, err := app.Open(version, app.WithChecker(c), app.WithLogger(log.New(stderr, false)))
+	are.NoErr(err) // unexpected error
+	// The module a is used by the workspace, it must only be checked once.
+	in := []string{filepath.Join(work, mod.WorkFilename), filepath.Join(work, "a")}
+	are.True(a.Check(context.Background(), in))   // expected drift
+	are.Equal(atomic.LoadInt32(&calls), int32(3)) // mismatch number of checks
+	are.Equal(stderr.String(), log.Prefix+"github.com/matryer/is: required at different versions: "+
+		"v1.2.0 by example.com/work/a; v1.4.1 by example.com/work/b\n") // mismatch drift
+}
+
+func TestApp_Check_Workspace_Strict(t *testing.T) {
+	t.Parallel()
+	var (
+		are    = is.New(t)
+		stderr = new(strings.Builder)
+		work   = filepath.Join("..", "..", "testdata", "golden", "work")
+		c      = func(_ context.Context, f mod.Mod, _ goup.Config) chan goup.Message {
+			ch := make(chan goup.Message, 1)
+			if f.Module() == "example.com/work/a" {
+				ch <- goup.NewEntry(goup.ErrorLevel, "%s", oops)
+			}
+			close(ch)
+			return ch
+		}
+	)
+	a, err := app.Open(version, app.WithChecker(c), app.WithLogger(log.New(stderr, false)))
+	are.NoErr(err) // unexpected error
+	a.Strict = true
+	// The modules of the workspace are also listed, only b is not checked once a failed.
+	in := []string{filepath.Join(work, mod.WorkFilename), filepath.Join(work, "a"), filepath.Join(work, "b")}
+	are.True(a.Check(context.Background(), in)) // expected failure
+	are.Equal(stderr.String(), log.Prefix+oops+"\n"+
+		log.Prefix+"strict mode: 1 go.mod file(s) not checked\n") // mismatch output
+}
+
+func TestApp_Check_Jobs(t *testing.T) {
+	t.Parallel()
+	var (
+		are     = is.New(t)
+		running int32
+		started = make(chan struct{})
+		stderr  = new(strings.Builder)
+		work    = filepath.Join("..", "..", "testdata", "golden", "work")
+		c       = func(_ context.Context, f mod.Mod, _ goup.Config) chan goup.Message {
+			ch := make(chan goup.Message)
+			go func() {
+				defer close(ch)
+				if atomic.AddInt32(&running, 1) == 2 {
+					close(started)
+				}
+				if filepath.Base(f.Name()) == mod.WorkFilename {
+					// The workspace is the slowest one: it waits for another check.
+					select {
+					case <-started:
+					case <-time.After(time.Second):
+					}
+				}
+				ch <- goup.NewEntry(goup.WarnLevel, "%s: checked", f.Module())
+			}()
+			return ch
+		}
+	)
+	a, err := app.Open(version, app.WithChecker(c), app.WithLogger(log.New(stderr, false)))
+	are.NoErr(err) // unexpected error
+	a.Jobs = 2
+	are.True(a.Check(context.Background(), []string{filepath.Join(work, mod.WorkFilename)})) // expected warnings
+	select {
+	case <-started:
+	default:
+		t.Fatal("files not checked concurrently")
+	}
+	// The messages are printed in the order of the files.
+	are.Equal(stderr.String(), log.Prefix+filepath.Join(work, mod.WorkFilename)+": checked\n"+
+		log.Prefix+"example.com/work/a: checked\n"+
+		log.Prefix+"example.com/work/b: checked\n"+
+		log.Prefix+"github.com/matryer/is: required at different versions: "+
+		"v1.2.0 by example.com/work/a; v1.4.1 by example.com/work/b\n") // mismatch output
+}
+
+func TestApp_Check_Align(t *testing.T) {
+	t.Parallel()
+	var (
+		are  = is.New(t)
+		dir  = t.TempDir()
+		work = filepath.Join("..", "..", "testdata", "golden", "work")
+		c    = func(_ context.Context, _ mod.Mod, _ goup.Config) chan goup.Message {
+			ch := make(chan goup.Message)
+			close(ch)
+			return ch
+		}
+	)
+	for _, name := range []string{"a", "b"} {
+		b, err := os.ReadFile(filepath.Join(work, name, mod.Filename))
+		are.NoErr(err)                                                            // unexpected error
+		are.NoErr(os.MkdirAll(filepath.Join(dir, name), 0o700))                   // unexpected error
+		are.NoErr(os.WriteFile(filepath.Join(dir, name, mod.Filename), b, 0o600)) // unexpected error
+	}
+	// The hashes of the version to align on are already known, so none is downloaded.
+	const dep = "github.com/matryer/is "
+	err := os.WriteFile(filepath.Join(dir, "a", sum.Filename), []byte(
+		dep+"v1.2.0 h1:old=\n"+dep+"v1.2.0/go.mod h1:oldmod=\n"+dep+"v1.4.1 h1:new=\n"+dep+"v1.4.1/go.mod h1:newmod=\n",
+	), 0o600)
+	are.NoErr(err) // unexpected error
+	a, err := app.Open(version, app.WithChecker(c))
+	are.NoErr(err) // unexpected error
+	in := []string{filepath.Join(dir, "a"), filepath.Join(dir, "b")}
+	a.Config = goup.Config{Align: "lowest"}
+	are.True(a.Check(context.Background(), in)) // expected unknown alignment
+	a.Config = goup.Config{Align: goup.AlignHighest}
+	are.True(a.Check(context.Background(), in)) // expected misalignment
+	a.Config = goup.Config{Align: goup.AlignHighest, ForceUpdate: true}
+	are.True(!a.Check(context.Background(), in)) // unexpected failure
+	b, err := os.ReadFile(filepath.Join(dir, "a", mod.Filename))
+	are.NoErr(err)                                                        // unexpected error
+	are.True(strings.Contains(string(b), "github.com/matryer/is v1.4.1")) // mismatch alignment
+	b, err = os.ReadFile(filepath.Join(dir, "a", sum.Filename))
+	are.NoErr(err)                                                            // unexpected error
+	are.True(!strings.Contains(string(b), "github.com/matryer/is v1.2.0 h1")) // unexpected hash of the old version
+	are.True(!a.Check(context.Background(), in))                              // expected aligned files
+}
+
 type checker struct{}
 
 // check implements the goup.Checker func.
@@ -0,0 +1,307 @@
+// Copyright (c) 2020 Hervé Gouchet. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+// Package config provides methods to handle the project configuration file.
+package config
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/rvflash/goup/internal/errors"
+	"github.com/rvflash/goup/internal/semver"
+	"github.com/rvflash/goup/pkg/goup"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Filenames lists the names of the configuration file, by order of preference.
+var Filenames = []string{".goup.yaml", ".goup.yml"}
+
+// File represents a configuration file.
+// Any undefined property keeps the value of the configuration on which the file is applied.
+// The output settings, like the format or the verbose mode, are only managed by flags.
+type File struct {
+	ExcludeIndirect *bool    `yaml:"exclude-indirect,omitempty"`
+	ForceUpdate     *bool    `yaml:"force,omitempty"`
+	Strict          *bool    `yaml:"strict,omitempty"`
+	CheckHead       *bool    `yaml:"head,omitempty"`
+	VerifySum       *bool    `yaml:"verify,omitempty"`
+	Directives      *bool    `yaml:"directives,omitempty"`
+	Update          string   `yaml:"update,omitempty"`
+	Timeout         string   `yaml:"timeout,omitempty"`
+	DepTimeout      string   `yaml:"dep-timeout,omitempty"`
+	Retries         *int     `yaml:"retries,omitempty"`
+	MaxRequests     *int     `yaml:"max-requests,omitempty"`
+	RateLimit       *float64 `yaml:"rate-limit,omitempty"`
+	CacheDir        string   `yaml:"cache-dir,omitempty"`
+	CacheTTL        string   `yaml:"cache-ttl,omitempty"`
+	Deprecated      string   `yaml:"deprecated,omitempty"`
+	GoReleases      string   `yaml:"go-releases,omitempty"`
+	Hosts           []string `yaml:"hosts,omitempty"`
+	Insecure        []string `yaml:"insecure,omitempty"`
+	OnlyReleases    []string `yaml:"only-releases,omitempty"`
+	Rewrite         []string `yaml:"rewrite,omitempty"`
+	Proxy           string   `yaml:"proxy,omitempty"`
+	NoProxy         []string `yaml:"no-proxy,omitempty"`
+	SumDB           string   `yaml:"sumdb,omitempty"`
+	NoSumDB         []string `yaml:"no-sumdb,omitempty"`
+	Modules         []Module `yaml:"modules,omitempty"`
+}
+
+// Module overrides the settings for the modules matching its path.
+type Module struct {
+	Path         string `yaml:"path"`
+	Update       string `yaml:"update,omitempty"`
+	Ignore       bool   `yaml:"ignore,omitempty"`
+	OnlyReleases bool   `yaml:"only-releases,omitempty"`
+	Versions     string `yaml:"versions,omitempty"`
+}
+
+// Find looks for a configuration file from the given directory upward.
+// It returns an empty path if no file is found.
+func Find(dir string) (string, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	for {
+		for _, name := range Filenames {
+			path := filepath.Join(dir, name)
+			if _, err = os.Stat(path); err == nil {
+				return path, nil
+			}
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", nil
+		}
+		dir = parent
+	}
+}
+
+// Load parses and validates the configuration file behind this path.
+func Load(path string) (*File, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", errors.ErrConfig, err.Error())
+	}
+	f := new(File)
+	if err = yaml.Unmarshal(b, f); err != nil {
+		return nil, fmt.Errorf("%w: %s: %s", errors.ErrConfig, path, err.Error())
+	}
+	if err = f.validate(); err != nil {
+		return nil, fmt.Errorf("%w: %s: %s", errors.ErrConfig, path, err.Error())
+	}
+	return f, nil
+}
+
+// New returns the configuration file describing this configuration.
+func New(c goup.Config) *File {
+	f := &File{
+		ExcludeIndirect: &c.ExcludeIndirect,
+		ForceUpdate:     &c.ForceUpdate,
+		Strict:          &c.Strict,
+		CheckHead:       &c.CheckHead,
+		VerifySum:       &c.VerifySum,
+		Directives:      &c.UpdateDirectives,
+		Update:          mode(c),
+		Timeout:         c.Timeout.String(),
+		DepTimeout:      c.DepTimeout.String(),
+		Retries:         &c.Retries,
+		MaxRequests:     &c.MaxRequests,
+		RateLimit:       &c.RateLimit,
+		CacheDir:        c.CacheDir,
+		CacheTTL:        c.CacheTTL.String(),
+		Deprecated:      c.Deprecated,
+		GoReleases:      c.GoReleases,
+		Hosts:           split(c.HostPatterns),
+		Insecure:        split(c.InsecurePatterns),
+		OnlyReleases:    split(c.OnlyReleases),
+		Rewrite:         split(c.Rewrite),
+		Proxy:           c.ProxyURLs,
+		NoProxy:         split(c.NoProxyPatterns),
+		SumDB:           c.SumDB,
+		NoSumDB:         split(c.NoSumDBPatterns),
+	}
+	for _, m := range c.Modules {
+		f.Modules = append(f.Modules, Module{
+			Path:         m.Path,
+			Update:       m.Mode,
+			Ignore:       m.Ignore,
+			OnlyReleases: m.OnlyReleases,
+			Versions:     m.Versions,
+		})
+	}
+	return f
+}
+
+// Apply applies the properties defined in the file on the configuration.
+func (f *File) Apply(c *goup.Config) {
+	if f == nil || c == nil {
+		return
+	}
+	setBool(&c.ExcludeIndirect, f.ExcludeIndirect)
+	setBool(&c.ForceUpdate, f.ForceUpdate)
+	setBool(&c.Strict, f.Strict)
+	setBool(&c.CheckHead, f.CheckHead)
+	setBool(&c.VerifySum, f.VerifySum)
+	setBool(&c.UpdateDirectives, f.Directives)
+	if f.Update != "" {
+		c.Major = f.Update == goup.MajorMode
+		c.MajorMinor = f.Update == goup.MinorMode
+	}
+	setDuration(&c.Timeout, f.Timeout)
+	setDuration(&c.DepTimeout, f.DepTimeout)
+	setInt(&c.Retries, f.Retries)
+	setInt(&c.MaxRequests, f.MaxRequests)
+	setFloat(&c.RateLimit, f.RateLimit)
+	setString(&c.CacheDir, f.CacheDir)
+	setDuration(&c.CacheTTL, f.CacheTTL)
+	setString(&c.Deprecated, f.Deprecated)
+	setString(&c.GoReleases, f.GoReleases)
+	setString(&c.HostPatterns, join(f.Hosts))
+	setString(&c.InsecurePatterns, join(f.Insecure))
+	setString(&c.OnlyReleases, join(f.OnlyReleases))
+	setString(&c.Rewrite, join(f.Rewrite))
+	setString(&c.ProxyURLs, f.Proxy)
+	setString(&c.NoProxyPatterns, join(f.NoProxy))
+	setString(&c.SumDB, f.SumDB)
+	setString(&c.NoSumDBPatterns, join(f.NoSumDB))
+	if len(f.Modules) > 0 {
+		c.Modules = make([]goup.ModuleConfig, len(f.Modules))
+		for k, m := range f.Modules {
+			c.Modules[k] = goup.ModuleConfig{
+				Path:         m.Path,
+				Mode:         m.Update,
+				Ignore:       m.Ignore,
+				OnlyReleases: m.OnlyReleases,
+				Versions:     m.Versions,
+			}
+		}
+	}
+}
+
+// Write writes the configuration file as YAML.
+func (f *File) Write(w io.Writer) error {
+	if f == nil || w == nil {
+		return errors.ErrMissing
+	}
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(f); err != nil {
+		return err
+	}
+	return enc.Close()
+}
+
+func (f *File) validate() error {
+	if err := validMode(f.Update); err != nil {
+		return err
+	}
+	switch f.Deprecated {
+	case "", goup.DeprecatedError, goup.DeprecatedWarn, goup.DeprecatedInfo:
+	default:
+		return fmt.Errorf("unknown deprecation level: %q", f.Deprecated)
+	}
+	if f.MaxRequests != nil && *f.MaxRequests < 0 {
+		return fmt.Errorf("invalid max-requests: %d", *f.MaxRequests)
+	}
+	if f.Retries != nil && *f.Retries < 0 {
+		return fmt.Errorf("invalid retries: %d", *f.Retries)
+	}
+	if f.RateLimit != nil && *f.RateLimit < 0 {
+		return fmt.Errorf("invalid rate-limit: %g", *f.RateLimit)
+	}
+	for _, d := range []string{f.Timeout, f.DepTimeout, f.CacheTTL} {
+		if d == "" {
+			continue
+		}
+		if _, err := time.ParseDuration(d); err != nil {
+			return err
+		}
+	}
+	for _, m := range f.Modules {
+		if m.Path == "" {
+			return errors.NewMissingData("module path")
+		}
+		if err := validMode(m.Update); err != nil {
+			return fmt.Errorf("%s: %w", m.Path, err)
+		}
+		if _, err := semver.ParseRange(m.Versions); err != nil {
+			return fmt.Errorf("%s: %w", m.Path, err)
+		}
+	}
+	return nil
+}
+
+func validMode(s string) error {
+	switch s {
+	case "", goup.PatchMode, goup.MinorMode, goup.MajorMode:
+		return nil
+	default:
+		return fmt.Errorf("unknown update mode: %q", s)
+	}
+}
+
+func mode(c goup.Config) string {
+	switch {
+	case c.Major:
+		return goup.MajorMode
+	case c.MajorMinor:
+		return goup.MinorMode
+	default:
+		return goup.PatchMode
+	}
+}
+
+const comma = ","
+
+func join(a []string) string {
+	return strings.Join(a, comma)
+}
+
+func split(s string) []string {
+	var res []string
+	for _, v := range strings.Split(s, comma) {
+		if v = strings.TrimSpace(v); v != "" {
+			res = append(res, v)
+		}
+	}
+	return res
+}
+
+func setBool(dst, src *bool) {
+	if src != nil {
+		*dst = *src
+	}
+}
+
+func setInt(dst, src *int) {
+	if src != nil {
+		*dst = *src
+	}
+}
+
+func setFloat(dst, src *float64) {
+	if src != nil {
+		*dst = *src
+	}
+}
+
+func setString(dst *string, src string) {
+	if src != "" {
+		*dst = src
+	}
+}
+
+func setDuration(dst *time.Duration, src string) {
+	if d, err := time.ParseDuration(src); err == nil {
+		*dst = d
+	}
+}
@@ -0,0 +1,127 @@
+// Copyright (c) 2020 Hervé Gouchet. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package config_test
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/matryer/is"
+	"github.com/rvflash/goup/internal/config"
+	errup "github.com/rvflash/goup/internal/errors"
+	"github.com/rvflash/goup/pkg/goup"
+)
+
+const project = "testdata/project"
+
+func TestFind(t *testing.T) {
+	t.Parallel()
+	var (
+		are = is.New(t)
+		dt  = map[string]struct {
+			in  string
+			out string
+		}{
+			"current": {in: project, out: filepath.Join(project, config.Filenames[0])},
+			"parent":  {in: filepath.Join(project, "sub"), out: filepath.Join(project, config.Filenames[0])},
+		}
+	)
+	for name, ts := range dt {
+		tt := ts
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			out, err := config.Find(tt.in)
+			are.NoErr(err) // unexpected error
+			want, err := filepath.Abs(tt.out)
+			are.NoErr(err)       // unexpected error
+			are.Equal(out, want) // mismatch result
+		})
+	}
+	t.Run("not found", func(t *testing.T) {
+		t.Parallel()
+		out, err := config.Find(t.TempDir())
+		are.NoErr(err)     // unexpected error
+		are.Equal(out, "") // mismatch result
+	})
+}
+
+func TestLoad(t *testing.T) {
+	t.Parallel()
+	var (
+		are = is.New(t)
+		dt  = map[string]struct {
+			in  string
+			err error
+		}{
+			"not found":  {in: "testdata/not-found.yaml", err: errup.ErrConfig},
+			"invalid":    {in: "testdata/invalid.yaml", err: errup.ErrConfig},
+			"deprecated": {in: "testdata/deprecated.yaml", err: errup.ErrConfig},
+			"rate":       {in: "testdata/rate.yaml", err: errup.ErrConfig},
+			"ok":         {in: filepath.Join(project, config.Filenames[0])},
+		}
+	)
+	for name, ts := range dt {
+		tt := ts
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			_, err := config.Load(tt.in)
+			are.True(errors.Is(err, tt.err)) // mismatch error
+		})
+	}
+}
+
+func TestFile_Apply(t *testing.T) {
+	t.Parallel()
+	are := is.New(t)
+	f, err := config.Load(filepath.Join(project, config.Filenames[0]))
+	are.NoErr(err) // unexpected error
+	c := goup.Config{Major: true, Strict: true, Timeout: time.Minute, OnlyReleases: "example.com/*"}
+	f.Apply(&c)
+	are.True(c.ExcludeIndirect)                                     // mismatch exclude indirect
+	are.True(c.Strict)                                              // mismatch strict
+	are.True(c.CheckHead)                                           // mismatch head
+	are.True(c.VerifySum)                                           // mismatch verify
+	are.True(c.UpdateDirectives)                                    // mismatch directives
+	are.True(!c.Major)                                              // mismatch major
+	are.True(c.MajorMinor)                                          // mismatch minor
+	are.Equal(c.Timeout, 30*time.Second)                            // mismatch timeout
+	are.Equal(c.DepTimeout, 5*time.Second)                          // mismatch dependency timeout
+	are.Equal(c.Retries, 1)                                         // mismatch retries
+	are.Equal(c.MaxRequests, 8)                                     // mismatch max requests
+	are.Equal(c.RateLimit, 2.5)                                     // mismatch rate limit
+	are.Equal(c.Deprecated, goup.DeprecatedError)                   // mismatch deprecation level
+	are.Equal(c.GoReleases, "https://go.example.com/dl/?mode=json") // mismatch releases URL
+	are.Equal(c.OnlyReleases, "github.com/rvflash/*")               // mismatch only releases
+	are.Equal(c.Rewrite, "github.com/rvflash/workr")                // mismatch rewrite
+	are.Equal(c.SumDB, "sum.golang.org")                            // mismatch checksum database
+	are.Equal(c.NoSumDBPatterns, "example.com/*")                   // mismatch no checksum database
+	are.Equal(len(c.Modules), 2)                                    // mismatch modules
+	are.Equal(c.Modules[0], goup.ModuleConfig{Path: "golang.org/x/*", Mode: goup.PatchMode, Versions: "<v1"})
+	are.Equal(c.Modules[1], goup.ModuleConfig{Path: "example.com/legacy", Ignore: true})
+}
+
+func TestFile_Write(t *testing.T) {
+	t.Parallel()
+	var (
+		are = is.New(t)
+		buf = new(strings.Builder)
+		c   = goup.Config{
+			MajorMinor:   true,
+			OnlyReleases: "a, b",
+			Timeout:      time.Minute,
+			Modules:      []goup.ModuleConfig{{Path: "example.com/*", Ignore: true}},
+		}
+	)
+	are.True(errors.Is((*config.File)(nil).Write(buf), errup.ErrMissing)) // mismatch error
+	are.NoErr(config.New(c).Write(buf))                                   // unexpected error
+	out := buf.String()
+	are.True(strings.Contains(out, "update: minor\n"))                             // mismatch mode
+	are.True(strings.Contains(out, "timeout: 1m0s\n"))                             // mismatch timeout
+	are.True(strings.Contains(out, "only-releases:\n  - a\n  - b\n"))              // mismatch only releases
+	are.True(strings.Contains(out, "  - path: example.com/*\n    ignore: true\n")) // mismatch modules
+}
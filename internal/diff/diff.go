@@ -0,0 +1,169 @@
+// Copyright (c) 2020 Hervé Gouchet. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+// Package diff provides methods to compare two texts line by line.
+package diff
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// Context is the number of unchanged lines printed around each change.
+const Context = 3
+
+const (
+	opEqual  = ' '
+	opDelete = '-'
+	opInsert = '+'
+
+	noNewline = "\\ No newline at end of file\n"
+)
+
+type edit struct {
+	op   byte
+	line string
+}
+
+// Unified returns the differences between oldText and newText in the unified format.
+// oldName and newName are used as labels of the files. It returns nil if both texts are equal.
+func Unified(oldName, newName string, oldText, newText []byte) []byte {
+	if bytes.Equal(oldText, newText) {
+		return nil
+	}
+	edits := compare(lines(oldText), lines(newText))
+	buf := new(bytes.Buffer)
+	_, _ = fmt.Fprintf(buf, "--- %s\n+++ %s\n", oldName, newName)
+	var oldLine, newLine int
+	for start := 0; start < len(edits); {
+		first, last, ok := hunk(edits, start)
+		if !ok {
+			break
+		}
+		// Counts the lines before the hunk.
+		for _, e := range edits[start:first] {
+			oldLine, newLine = move(e, oldLine, newLine)
+		}
+		var oldLen, newLen int
+		for _, e := range edits[first:last] {
+			oldLen, newLen = move(e, oldLen, newLen)
+		}
+		_, _ = fmt.Fprintf(buf, "@@ -%s +%s @@\n", span(oldLine, oldLen), span(newLine, newLen))
+		for _, e := range edits[first:last] {
+			buf.WriteByte(e.op)
+			buf.WriteString(e.line)
+			if !strings.HasSuffix(e.line, "\n") {
+				buf.WriteString("\n" + noNewline)
+			}
+		}
+		oldLine += oldLen
+		newLine += newLen
+		start = last
+	}
+	return buf.Bytes()
+}
+
+// hunk returns the bounds of the next hunk from the start index.
+// Two changes separated by less than twice the context are in the same hunk.
+func hunk(edits []edit, start int) (first, last int, ok bool) {
+	next := func(i int) int {
+		for ; i < len(edits); i++ {
+			if edits[i].op != opEqual {
+				return i
+			}
+		}
+		return -1
+	}
+	i := next(start)
+	if i < 0 {
+		return 0, 0, false
+	}
+	first = max(start, i-Context)
+	for {
+		end := i
+		for end < len(edits) && edits[end].op != opEqual {
+			end++
+		}
+		if i = next(end); i < 0 || i-end > 2*Context {
+			return first, min(len(edits), end+Context), true
+		}
+	}
+}
+
+func move(e edit, oldLine, newLine int) (int, int) {
+	switch e.op {
+	case opDelete:
+		oldLine++
+	case opInsert:
+		newLine++
+	default:
+		oldLine++
+		newLine++
+	}
+	return oldLine, newLine
+}
+
+func span(line, n int) string {
+	if n == 0 {
+		// An empty range starts at the line before.
+		return fmt.Sprintf("%d,0", line)
+	}
+	if n == 1 {
+		return fmt.Sprintf("%d", line+1)
+	}
+	return fmt.Sprintf("%d,%d", line+1, n)
+}
+
+// compare returns the edit script to transform a into b, based on their longest common subsequence.
+func compare(a, b []string) []edit {
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+	res := make([]edit, 0, len(a)+len(b))
+	var i, j int
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			res = append(res, edit{op: opEqual, line: a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			res = append(res, edit{op: opDelete, line: a[i]})
+			i++
+		default:
+			res = append(res, edit{op: opInsert, line: b[j]})
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		res = append(res, edit{op: opDelete, line: a[i]})
+	}
+	for ; j < len(b); j++ {
+		res = append(res, edit{op: opInsert, line: b[j]})
+	}
+	return res
+}
+
+// lines splits the text after each new line, keeping it.
+func lines(b []byte) []string {
+	if len(b) == 0 {
+		return nil
+	}
+	res := strings.SplitAfter(string(b), "\n")
+	if res[len(res)-1] == "" {
+		res = res[:len(res)-1]
+	}
+	return res
+}
@@ -0,0 +1,78 @@
+// Copyright (c) 2020 Hervé Gouchet. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package diff_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/matryer/is"
+	"github.com/rvflash/goup/internal/diff"
+)
+
+const goMod = `module example.com/group/go
+
+go 1.24
+
+require (
+	github.com/a/a v1.0.0
+	github.com/b/b v1.0.0
+	github.com/c/c v1.0.0
+	github.com/d/d v1.0.0
+	github.com/e/e v1.0.0
+	github.com/f/f v1.0.0
+	github.com/g/g v1.0.0
+	github.com/h/h v1.0.0
+	github.com/i/i v1.0.0
+)
+`
+
+func TestUnified(t *testing.T) {
+	t.Parallel()
+	var (
+		are = is.New(t)
+		dt  = map[string]struct {
+			old, new string
+			out      string
+		}{
+			"Default": {},
+			"Equal":   {old: goMod, new: goMod},
+			"Creation": {
+				new: "a\nb\n",
+				out: "--- a/go.mod\n+++ b/go.mod\n@@ -0,0 +1,2 @@\n+a\n+b\n",
+			},
+			"No newline": {
+				old: "a\nb",
+				new: "a\nc\n",
+				out: "--- a/go.mod\n+++ b/go.mod\n@@ -1,2 +1,2 @@\n a\n-b\n\\ No newline at end of file\n+c\n",
+			},
+			"One hunk": {
+				old: goMod,
+				new: strings.Replace(goMod, "github.com/b/b v1.0.0", "github.com/b/b v1.0.1", 1),
+				out: "--- a/go.mod\n+++ b/go.mod\n@@ -4,7 +4,7 @@\n \n require (\n \tgithub.com/a/a v1.0.0\n" +
+					"-\tgithub.com/b/b v1.0.0\n+\tgithub.com/b/b v1.0.1\n \tgithub.com/c/c v1.0.0\n" +
+					" \tgithub.com/d/d v1.0.0\n \tgithub.com/e/e v1.0.0\n",
+			},
+			"Two hunks": {
+				old: goMod,
+				new: strings.NewReplacer("github.com/a/a v1.0.0", "github.com/a/a v1.1.0",
+					"github.com/i/i v1.0.0", "github.com/i/i v1.1.0").Replace(goMod),
+				out: "--- a/go.mod\n+++ b/go.mod\n@@ -3,7 +3,7 @@\n go 1.24\n \n require (\n" +
+					"-\tgithub.com/a/a v1.0.0\n+\tgithub.com/a/a v1.1.0\n \tgithub.com/b/b v1.0.0\n" +
+					" \tgithub.com/c/c v1.0.0\n \tgithub.com/d/d v1.0.0\n" +
+					"@@ -11,5 +11,5 @@\n \tgithub.com/f/f v1.0.0\n \tgithub.com/g/g v1.0.0\n \tgithub.com/h/h v1.0.0\n" +
+					"-\tgithub.com/i/i v1.0.0\n+\tgithub.com/i/i v1.1.0\n )\n",
+			},
+		}
+	)
+	for name, ts := range dt {
+		tt := ts
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			out := diff.Unified("a/go.mod", "b/go.mod", []byte(tt.old), []byte(tt.new))
+			are.Equal(string(out), tt.out) // mismatch diff
+		})
+	}
+}
@@ -33,18 +33,30 @@ func (e upError) Error() string {
 }
 
 const (
+	// ErrChecksum is returned when the hash of a module version differs from the known one.
+	ErrChecksum = upError("checksum mismatch")
+	// ErrConfig is returned when the configuration file is invalid.
+	ErrConfig = upError("invalid configuration")
+	// ErrDirect is returned when the module must be fetched directly from its VCS.
+	ErrDirect = upError("direct fetch expected")
 	// ErrExpectedTag is returned when the version is not a release tag.
 	ErrExpectedTag = upError("release tag expected")
-	// ErrFetch is returned when the fetching of versions failed.
-	ErrFetch = upError("failed to list tags")
+	// ErrFetch is returned when a remote request failed, like the listing of the versions or of the Go releases.
+	ErrFetch = upError("failed to fetch")
+	// ErrFormat is returned when the output format is unknown.
+	ErrFormat = upError("unknown format")
 	// ErrMissing is returned when the data is missing.
 	ErrMissing = upError("missing data")
 	// ErrMod is returned when the go.mod file is invalid.
 	ErrMod = upError("invalid go.mod")
+	// ErrNotFound is returned when the remote does not know the module.
+	ErrNotFound = upError("not found")
 	// ErrNotModified is returned when the file has not changed.
 	ErrNotModified = upError("not modified")
 	// ErrRepository is returned when the repository is invalid.
 	ErrRepository = upError("invalid repository")
+	// ErrSum is returned when the go.sum file is invalid.
+	ErrSum = upError("invalid go.sum")
 	// ErrSystem is returned when the VCS does not respond to the remote request.
 	ErrSystem = upError("invalid VCS")
 )
@@ -24,7 +24,7 @@ func TestNewSecurityIssue(t *testing.T) {
 	t.Parallel()
 	is.New(t).Equal(
 		errup.NewSecurityIssue("http://example.com").Error(),
-		"unsecured call to http://example.com cancelled: failed to list tags",
+		"unsecured call to http://example.com cancelled: failed to fetch",
 	)
 }
 
@@ -0,0 +1,139 @@
+// Copyright (c) 2020 Hervé Gouchet. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+// Package imports provides methods to rewrite the import paths of the Go files of a module.
+package imports
+
+import (
+	"bytes"
+	"go/ast"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/rvflash/goup/internal/errors"
+
+	"golang.org/x/mod/module"
+)
+
+const (
+	goExt     = ".go"
+	modFile   = "go.mod"
+	testdata  = "testdata"
+	vendor    = "vendor"
+	tabWidth  = 8
+	printMode = printer.UseSpaces | printer.TabIndent
+)
+
+// Rewrite replaces the imports of the old module path, or of one of its packages, by the new module path
+// in the Go files of the module living in this directory. As with the go command, the nested modules,
+// the vendor and testdata directories, and those starting with a dot or an underscore are ignored.
+// Only the import paths are changed, the files are printed as gofmt does.
+// With write, the files are updated, otherwise they are left untouched.
+// It returns the sorted list of the files to rewrite or rewritten.
+func Rewrite(dir, oldPath, newPath string, write bool) ([]string, error) {
+	if dir == "" || oldPath == "" || newPath == "" {
+		return nil, errors.ErrMissing
+	}
+	var res []string
+	err := filepath.WalkDir(dir, func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if name != dir && skipDir(name) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if filepath.Ext(name) != goExt {
+			return nil
+		}
+		ok, err := rewriteFile(name, oldPath, newPath, write)
+		if err != nil {
+			return err
+		}
+		if ok {
+			res = append(res, name)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(res)
+	return res, nil
+}
+
+func skipDir(name string) bool {
+	base := filepath.Base(name)
+	if base == vendor || base == testdata || strings.HasPrefix(base, ".") || strings.HasPrefix(base, "_") {
+		return true
+	}
+	// A nested module has its own dependencies.
+	_, err := os.Stat(filepath.Join(name, modFile))
+	return err == nil
+}
+
+// rewriteFile rewrites the matching imports of this file and returns true if there is any.
+func rewriteFile(name, oldPath, newPath string, write bool) (bool, error) {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, name, nil, parser.ParseComments)
+	if err != nil {
+		return false, err
+	}
+	var changed bool
+	for _, imp := range f.Imports {
+		p, err := strconv.Unquote(imp.Path.Value)
+		if err != nil {
+			continue
+		}
+		if rest, ok := cut(p, oldPath); ok {
+			imp.Path.Value = strconv.Quote(newPath + rest)
+			changed = true
+		}
+	}
+	if !changed || !write {
+		return changed, nil
+	}
+	// The new paths may break the order of the imports.
+	ast.SortImports(fset, f)
+	var buf bytes.Buffer
+	cnf := printer.Config{Mode: printMode, Tabwidth: tabWidth}
+	if err = cnf.Fprint(&buf, fset, f); err != nil {
+		return false, err
+	}
+	fi, err := os.Stat(name)
+	if err != nil {
+		return false, err
+	}
+	return true, os.WriteFile(name, buf.Bytes(), fi.Mode().Perm())
+}
+
+// cut returns the part of the import path after the module path, if the package belongs to this module.
+// Without major suffix, the packages of another major version of the module, like example.com/lib/v2
+// for example.com/lib, do not.
+func cut(importPath, modulePath string) (string, bool) {
+	if importPath == modulePath {
+		return "", true
+	}
+	if !strings.HasPrefix(importPath, modulePath+"/") {
+		return "", false
+	}
+	rest := importPath[len(modulePath):]
+	if _, pathMajor, ok := module.SplitPathVersion(modulePath); ok && pathMajor != "" {
+		return rest, true
+	}
+	elem := strings.SplitN(rest[1:], "/", 2)[0]
+	if _, pathMajor, ok := module.SplitPathVersion(modulePath + "/" + elem); ok && pathMajor != "" {
+		return "", false
+	}
+	return rest, true
+}
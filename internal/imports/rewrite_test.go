@@ -0,0 +1,131 @@
+// Copyright (c) 2020 Hervé Gouchet. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package imports_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/matryer/is"
+	errup "github.com/rvflash/goup/internal/errors"
+	"github.com/rvflash/goup/internal/imports"
+)
+
+const (
+	oldPath = "example.com/lib/v2"
+	newPath = "example.com/lib/v3"
+)
+
+var files = map[string]string{
+	"main.go": `package main
+
+import (
+	"fmt"
+
+	// The library.
+	lib "example.com/lib/v2"
+	"example.com/lib/v2/sub"
+	"example.com/other"
+)
+
+func main() {
+	fmt.Println(lib.Name, sub.Name, other.Name) // Keep this comment.
+}
+`,
+	"pkg/none.go": `package pkg
+
+import "example.com/lib/v22"
+
+var _ = v22.Name
+`,
+	"pkg/pkg_test.go":    "package pkg\n\nimport _ \"example.com/lib/v2\"\n",
+	"vendor/a/a.go":      "package a\n\nimport _ \"example.com/lib/v2\"\n",
+	"testdata/b/b.go":    "package b\n\nimport _ \"example.com/lib/v2\"\n",
+	".hidden/c.go":       "package c\n\nimport _ \"example.com/lib/v2\"\n",
+	"nested/go.mod":      "module example.com/nested\n",
+	"nested/d.go":        "package d\n\nimport _ \"example.com/lib/v2\"\n",
+	"docs/readme.md":     "example.com/lib/v2\n",
+	"pkg/unrelated.go":   "package pkg\n",
+	"pkg/v1/v1.go":       "package v1\n\nimport _ \"example.com/lib\"\n",
+	"pkg/other/other.go": "package other\n\nimport _ \"example.com/lib/v2/v2\"\n",
+}
+
+func TestRewrite(t *testing.T) {
+	t.Parallel()
+	are := is.New(t)
+	_, err := imports.Rewrite("", oldPath, newPath, true)
+	are.True(errors.Is(err, errup.ErrMissing)) // mismatch error
+
+	dir := newModule(t)
+	res, err := imports.Rewrite(dir, oldPath, newPath, false)
+	are.NoErr(err) // unexpected error
+	exp := []string{
+		filepath.Join(dir, "main.go"),
+		filepath.Join(dir, "pkg", "other", "other.go"),
+		filepath.Join(dir, "pkg", "pkg_test.go"),
+	}
+	are.Equal(res, exp)                                  // mismatch files to rewrite
+	are.Equal(read(t, dir, "main.go"), files["main.go"]) // unexpected rewrite
+
+	res, err = imports.Rewrite(dir, oldPath, newPath, true)
+	are.NoErr(err)      // unexpected error
+	are.Equal(res, exp) // mismatch files rewritten
+	are.Equal(read(t, dir, "main.go"), `package main
+
+import (
+	"fmt"
+
+	// The library.
+	lib "example.com/lib/v3"
+	"example.com/lib/v3/sub"
+	"example.com/other"
+)
+
+func main() {
+	fmt.Println(lib.Name, sub.Name, other.Name) // Keep this comment.
+}
+`) // mismatch content
+	are.Equal(read(t, dir, "pkg/pkg_test.go"), "package pkg\n\nimport _ \"example.com/lib/v3\"\n") // mismatch test file
+	are.Equal(read(t, dir, "pkg/none.go"), files["pkg/none.go"])                                   // unexpected rewrite
+	are.Equal(read(t, dir, "nested/d.go"), files["nested/d.go"])                                   // unexpected rewrite
+	are.Equal(read(t, dir, "vendor/a/a.go"), files["vendor/a/a.go"])                               // unexpected rewrite
+}
+
+func TestRewrite_Major(t *testing.T) {
+	t.Parallel()
+	are := is.New(t)
+	dir := newModule(t)
+	// The packages of a major version of the module are not those of the module without suffix.
+	res, err := imports.Rewrite(dir, "example.com/lib", newPath, true)
+	are.NoErr(err)                                                                             // unexpected error
+	are.Equal(res, []string{filepath.Join(dir, "pkg", "v1", "v1.go")})                         // mismatch files
+	are.Equal(read(t, dir, "pkg/v1/v1.go"), "package v1\n\nimport _ \"example.com/lib/v3\"\n") // mismatch content
+}
+
+func newModule(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		name = filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(name), 0o700); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(name, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func read(t *testing.T, dir, name string) string {
+	t.Helper()
+	b, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(name)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
@@ -18,6 +18,13 @@ const (
 // Match reports whether any path prefix of target matches one of the glob patterns.
 // globs is a comma-separated list of glob patterns.
 func Match(globs, target string) (matched bool) {
+	_, matched = Prefix(globs, target)
+	return
+}
+
+// Prefix returns the first path prefix of target matching one of the glob patterns.
+// globs is a comma-separated list of glob patterns, tested in order.
+func Prefix(globs, target string) (prefix string, matched bool) {
 	if target == "" {
 		return
 	}
@@ -33,10 +40,11 @@ func Match(globs, target string) (matched bool) {
 		if len(src) > len(dst) {
 			continue
 		}
-		matched, _ = path.Match(glob, path.Join(dst[:len(src)]...))
+		prefix = path.Join(dst[:len(src)]...)
+		matched, _ = path.Match(glob, prefix)
 		if matched {
 			return
 		}
 	}
-	return
+	return "", false
 }
@@ -40,3 +40,31 @@ func TestMatch(t *testing.T) {
 		})
 	}
 }
+
+func TestPrefix(t *testing.T) {
+	t.Parallel()
+	var (
+		are = is.New(t)
+		dt  = map[string]struct {
+			glob   string
+			target string
+			prefix string
+		}{
+			"Default":    {},
+			"No glob":    {target: "a/b/c"},
+			"Wrong glob": {glob: "b/*", target: "a/b/c"},
+			"Glob":       {glob: "a/*", target: "a/b/c", prefix: "a/b"},
+			"Order":      {glob: "a/b/*,a/*", target: "a/b/c/d", prefix: "a/b/c"},
+			"Host":       {glob: "*/scm/*/*", target: "git.example.lan/scm/a/b/c", prefix: "git.example.lan/scm/a/b"},
+		}
+	)
+	for name, ts := range dt {
+		tt := ts
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			prefix, ok := path.Prefix(tt.glob, tt.target)
+			are.Equal(prefix, tt.prefix)   // mismatch prefix
+			are.Equal(ok, tt.prefix != "") // mismatch result
+		})
+	}
+}
@@ -0,0 +1,176 @@
+// Copyright (c) 2020 Hervé Gouchet. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+// Package release provides methods to list the releases of Go.
+package release
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/rvflash/goup/internal/errors"
+	"github.com/rvflash/goup/internal/vcs"
+
+	"golang.org/x/mod/semver"
+)
+
+const (
+	// Name prefixes the errors of this package.
+	Name = "go"
+	// DefaultURL lists all the releases of Go, as published on go.dev.
+	DefaultURL = "https://go.dev/dl/?mode=json&include=all"
+	// Default is the toolchain name meaning the one of the go command.
+	Default = "default"
+	// Supported is the number of the latest minor versions supported by the Go team.
+	Supported = 2
+)
+
+// prefix of the Go versions in the toolchain names.
+const prefix = "go"
+
+// Release is a Go release, as listed by go.dev/dl.
+type Release struct {
+	Version string `json:"version"`
+	Stable  bool   `json:"stable"`
+}
+
+// Releases is a list of Go releases.
+type Releases []Release
+
+// Fetch returns the Go releases listed by this URL.
+// The URL can be the one of a go.dev/dl mirror or a local file, with or without the file scheme.
+// Any failure, including an invalid list, returns errors.ErrFetch.
+func Fetch(ctx context.Context, client vcs.ClientChooser, auth vcs.BasicAuthentifier, rawURL string) (Releases, error) {
+	var (
+		rc  io.ReadCloser
+		err error
+	)
+	u, _ := url.Parse(rawURL)
+	if u == nil || u.Scheme == "" || u.Scheme == "file" {
+		name := rawURL
+		if u != nil && u.Scheme == "file" {
+			name = u.Path
+		}
+		rc, err = os.Open(name)
+		if err != nil {
+			return nil, vcs.Errorf(Name, errors.ErrFetch, err)
+		}
+	} else if rc, err = vcs.Get(ctx, client, auth, Name, rawURL); err != nil {
+		return nil, err
+	}
+	defer func() { _ = rc.Close() }()
+
+	var res Releases
+	if err = json.NewDecoder(rc).Decode(&res); err != nil {
+		return nil, vcs.Errorf(Name, errors.ErrFetch, rawURL, err)
+	}
+	return res, nil
+}
+
+// Latest returns the latest stable release, as a go directive version like 1.21.3.
+func (r Releases) Latest() string {
+	return r.latest(func(string) bool { return true })
+}
+
+// LatestPatch returns the latest stable release sharing the minor version of this one.
+func (r Releases) LatestPatch(version string) string {
+	mm := semver.MajorMinor(Semver(version))
+	if mm == "" {
+		return ""
+	}
+	return r.latest(func(v string) bool {
+		return semver.MajorMinor(v) == mm
+	})
+}
+
+// Supported returns true if the minor version of this one is one of the latest minor versions.
+// A version newer than the latest stable release is considered as supported.
+func (r Releases) Supported(version string) bool {
+	v := Semver(version)
+	if !semver.IsValid(v) {
+		return false
+	}
+	minors := r.supported()
+	if len(minors) == 0 {
+		return true
+	}
+	return semver.Compare(semver.MajorMinor(v), minors[0]) >= 0
+}
+
+// OldestSupported returns the latest stable release of the oldest minor version still supported.
+func (r Releases) OldestSupported() string {
+	minors := r.supported()
+	if len(minors) == 0 {
+		return ""
+	}
+	return r.latest(func(v string) bool {
+		return semver.MajorMinor(v) == minors[0]
+	})
+}
+
+// supported returns the sorted list of the minor versions supported, like v1.21.
+func (r Releases) supported() []string {
+	var minors []string
+	for _, x := range r {
+		if !x.Stable {
+			continue
+		}
+		mm := semver.MajorMinor(Semver(x.Version))
+		if mm != "" && !contains(minors, mm) {
+			minors = append(minors, mm)
+		}
+	}
+	semver.Sort(minors)
+	if len(minors) > Supported {
+		minors = minors[len(minors)-Supported:]
+	}
+	return minors
+}
+
+// Compare returns an integer comparing the Go versions v and w.
+// The result is 0 if v == w, -1 if v < w, or +1 if v > w.
+func Compare(v, w string) int {
+	return semver.Compare(Semver(v), Semver(w))
+}
+
+func (r Releases) latest(match func(v string) bool) string {
+	var res, max string
+	for _, x := range r {
+		v := Semver(x.Version)
+		if !x.Stable || !semver.IsValid(v) || !match(v) {
+			continue
+		}
+		if max == "" || semver.Compare(v, max) > 0 {
+			res, max = strings.TrimPrefix(x.Version, prefix), v
+		}
+	}
+	return res
+}
+
+// Semver returns the semantic version of this Go version, like v1.21.0-rc2 for go1.21rc2.
+// The go prefix of the toolchain names is optional.
+func Semver(version string) string {
+	v := strings.TrimPrefix(version, prefix)
+	var pre string
+	if i := strings.IndexFunc(v, func(r rune) bool { return r >= 'a' && r <= 'z' }); i > 0 {
+		v, pre = v[:i], "-"+v[i:]
+	}
+	if strings.Count(v, ".") == 1 {
+		v += ".0"
+	}
+	return "v" + v + pre
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,111 @@
+// Copyright (c) 2020 Hervé Gouchet. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package release_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/matryer/is"
+	errup "github.com/rvflash/goup/internal/errors"
+	"github.com/rvflash/goup/internal/release"
+	"github.com/rvflash/goup/internal/vcs"
+)
+
+const listFile = "../../testdata/release/go.json"
+
+func TestFetch(t *testing.T) {
+	t.Parallel()
+	buf, err := os.ReadFile(listFile)
+	abs, _ := filepath.Abs(listFile)
+	var (
+		are = is.New(t)
+		srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = w.Write(buf)
+		}))
+		bad = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = w.Write([]byte("<html>"))
+		}))
+		dt = map[string]struct {
+			in  string
+			len int
+			err error
+		}{
+			"Default":   {err: errup.ErrFetch},
+			"Not found": {in: "testdata/nope.json", err: errup.ErrFetch},
+			"Invalid":   {in: bad.URL, err: errup.ErrFetch},
+			"File":      {in: listFile, len: 6},
+			"File URL":  {in: "file://" + filepath.ToSlash(abs), len: 6},
+			"HTTP":      {in: srv.URL, len: 6},
+		}
+	)
+	defer srv.Close()
+	defer bad.Close()
+	are.NoErr(err) // unexpected error
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	for name, ts := range dt {
+		tt := ts
+		t.Run(name, func(_ *testing.T) {
+			res, err := release.Fetch(ctx, vcs.NewHTTPClient(time.Second, "127.0.0.1"), nil, tt.in)
+			are.True(errors.Is(err, tt.err)) // mismatch error
+			are.Equal(len(res), tt.len)      // mismatch result
+		})
+	}
+	_, err = release.Fetch(ctx, vcs.NewHTTPClient(time.Second, "127.0.0.1"), nil, bad.URL)
+	are.True(strings.HasPrefix(err.Error(), "go: failed to fetch: "+bad.URL)) // mismatch message
+}
+
+func TestReleases(t *testing.T) {
+	t.Parallel()
+	var (
+		are = is.New(t)
+		r   = release.Releases{
+			{Version: "go1.22rc1"},
+			{Version: "go1.21.5", Stable: true},
+			{Version: "go1.21.4", Stable: true},
+			{Version: "go1.20.12", Stable: true},
+			{Version: "go1.20", Stable: true},
+			{Version: "go1.19.13", Stable: true},
+		}
+	)
+	are.Equal(release.Releases(nil).Latest(), "")          // mismatch default
+	are.Equal(r.Latest(), "1.21.5")                        // mismatch latest
+	are.Equal(r.LatestPatch("go1.20.1"), "1.20.12")        // mismatch latest patch
+	are.Equal(r.LatestPatch("1.18"), "")                   // mismatch unknown minor
+	are.Equal(r.LatestPatch(""), "")                       // mismatch invalid
+	are.True(r.Supported("1.20"))                          // previous minor
+	are.True(r.Supported("1.22rc1"))                       // next minor
+	are.True(!r.Supported("1.19.13"))                      // unsupported minor
+	are.True(!r.Supported("unknown"))                      // invalid
+	are.True(release.Releases(nil).Supported("1.2"))       // without releases
+	are.Equal(r.OldestSupported(), "1.20.12")              // mismatch oldest supported
+	are.Equal(release.Releases(nil).OldestSupported(), "") // without releases
+}
+
+func TestSemver(t *testing.T) {
+	t.Parallel()
+	var (
+		are = is.New(t)
+		dt  = map[string]string{
+			"1.21":      "v1.21.0",
+			"1.21.3":    "v1.21.3",
+			"go1.21.3":  "v1.21.3",
+			"go1.21rc2": "v1.21.0-rc2",
+		}
+	)
+	for in, out := range dt {
+		are.Equal(release.Semver(in), out) // mismatch result
+	}
+}
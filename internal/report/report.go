@@ -0,0 +1,155 @@
+// Copyright (c) 2020 Hervé Gouchet. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+// Package report provides the structured result of a run and the methods to write it.
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	errs "github.com/rvflash/goup/internal/errors"
+	"github.com/rvflash/goup/pkg/goup"
+)
+
+// List of supported formats.
+const (
+	// Text is the default format: only the logs are printed.
+	Text = "text"
+	// JSON prints one JSON document by run.
+	JSON = "json"
+	// SARIF prints a SARIF 2.1.0 log, used by code scanning tools.
+	SARIF = "sarif"
+)
+
+// Writer must be implemented to write a report.
+type Writer func(w io.Writer, r *Report) error
+
+// Lookup returns the writer to use for this format.
+// With the text format, there is no report to write, so the writer is nil.
+func Lookup(format string) (Writer, error) {
+	switch format {
+	case "", Text:
+		return nil, nil
+	case JSON:
+		return WriteJSON, nil
+	case SARIF:
+		return WriteSARIF, nil
+	default:
+		return nil, fmt.Errorf("format %q: %w", format, errs.ErrFormat)
+	}
+}
+
+// New returns a new report.
+func New(version string) *Report {
+	return &Report{Version: version}
+}
+
+// Report is the result of a run.
+type Report struct {
+	Version string  `json:"version,omitempty"`
+	Files   []*File `json:"files"`
+}
+
+// Locator must be implemented to locate a dependency in a go.mod file.
+type Locator interface {
+	// Line returns the line number of the module path in the file or 0 if unknown.
+	Line(path string) int
+}
+
+// AddFile adds a new go.mod file to the report and returns it.
+// The locator is optional.
+func (r *Report) AddFile(path, module string, loc Locator) *File {
+	f := &File{
+		Path:         path,
+		Module:       module,
+		Dependencies: []*Dependency{},
+		loc:          loc,
+	}
+	r.Files = append(r.Files, f)
+	return f
+}
+
+// File is the result of the check of a go.mod file.
+// The go and toolchain directives are reported apart from the dependencies.
+type File struct {
+	Path         string        `json:"path"`
+	Module       string        `json:"module,omitempty"`
+	Dependencies []*Dependency `json:"dependencies"`
+	Directives   []*Dependency `json:"directives,omitempty"`
+	Errors       []string      `json:"errors,omitempty"`
+	// Aborted is true when the file has not been checked due to the strict mode.
+	Aborted bool `json:"aborted,omitempty"`
+
+	loc Locator
+}
+
+// AddError adds an error on the file.
+func (f *File) AddError(msg string) {
+	f.Errors = append(f.Errors, msg)
+}
+
+// Add adds the given message to the file.
+// Messages without dependency are only kept if they are errors.
+func (f *File) Add(msg goup.Message) {
+	if msg == nil {
+		return
+	}
+	if msg.Path() == "" {
+		if msg.Level() == goup.ErrorLevel {
+			f.AddError(fmt.Sprintf(msg.Format(), msg.Args()...))
+		}
+		return
+	}
+	d := &Dependency{
+		Path:    msg.Path(),
+		Version: msg.Version(),
+		Status:  msg.Status(),
+		Message: fmt.Sprintf(msg.Format(), msg.Args()...),
+	}
+	if f.loc != nil {
+		d.Line = f.loc.Line(d.Path)
+	}
+	if v := msg.NewVersion(); v != "" {
+		d.NewPath = msg.NewPath()
+		d.NewVersion = v
+		d.Update = msg.UpdateKind().String()
+	}
+	if d.err = msg.Err(); d.err != nil {
+		d.Error = d.err.Error()
+	}
+	if msg.Directive() {
+		d.directive = true
+		f.Directives = append(f.Directives, d)
+		return
+	}
+	f.Dependencies = append(f.Dependencies, d)
+}
+
+// Dependency is the result of the check of one dependency, or of one directive named by its path.
+type Dependency struct {
+	Path       string      `json:"path"`
+	Version    string      `json:"version"`
+	NewPath    string      `json:"newPath,omitempty"`
+	NewVersion string      `json:"newVersion,omitempty"`
+	Update     string      `json:"update,omitempty"`
+	Status     goup.Status `json:"status"`
+	Error      string      `json:"error,omitempty"`
+	Line       int         `json:"line,omitempty"`
+	Message    string      `json:"-"`
+
+	err       error
+	directive bool
+}
+
+// WriteJSON writes the report as an indented JSON document.
+func WriteJSON(w io.Writer, r *Report) error {
+	if w == nil || r == nil {
+		return errs.ErrMissing
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
@@ -0,0 +1,109 @@
+// Copyright (c) 2020 Hervé Gouchet. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package report_test
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/matryer/is"
+	errup "github.com/rvflash/goup/internal/errors"
+	"github.com/rvflash/goup/internal/report"
+	"github.com/rvflash/goup/pkg/goup"
+)
+
+const (
+	modPath = "example.com/group/go.mod"
+	modName = "example.com/group"
+	depName = "example.com/group/pkg"
+)
+
+func TestLookup(t *testing.T) {
+	t.Parallel()
+	var (
+		are = is.New(t)
+		dt  = map[string]struct {
+			in     string
+			writer bool
+			err    error
+		}{
+			"default": {},
+			"text":    {in: report.Text},
+			"json":    {in: report.JSON, writer: true},
+			"sarif":   {in: report.SARIF, writer: true},
+			"unknown": {in: "xml", err: errup.ErrFormat},
+		}
+	)
+	for name, ts := range dt {
+		tt := ts
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			w, err := report.Lookup(tt.in)
+			are.True(errors.Is(err, tt.err)) // mismatch error
+			are.Equal(w != nil, tt.writer)   // mismatch writer
+		})
+	}
+}
+
+func TestFile_Add(t *testing.T) {
+	t.Parallel()
+	var (
+		are = is.New(t)
+		rep = report.New("v1.0.0")
+		f   = rep.AddFile(modPath, modName, locator{depName: 4})
+	)
+	f.Add(nil)
+	f.Add(goup.NewEntry(goup.DebugLevel, "%s", "noop"))
+	f.Add(&goup.Entry{Kind: goup.ErrorLevel, Message: "%s: %s", Data: []interface{}{modName, "oops"}})
+	f.Add(&goup.Entry{Kind: goup.WarnLevel, Dep: depName, Current: "v1.2.3", Proposed: "v1.3.0", State: goup.Outdated})
+	f.Add(&goup.Entry{Dep: depName, Current: "v1.2.3", State: goup.Failed, Cause: errup.ErrFetch})
+	f.Add(&goup.Entry{
+		Kind: goup.WarnLevel, Dep: depName, Current: "v1.2.3", Proposed: "v2.0.1", ProposedPath: depName + "/v2",
+		State: goup.MajorAvailable,
+	})
+	f.Add(&goup.Entry{
+		Kind: goup.WarnLevel, Dep: "go", Current: "1.19", Proposed: "1.22.0", State: goup.Outdated, IsDirective: true,
+	})
+
+	are.Equal(len(rep.Files), 1)                               // mismatch files
+	are.Equal(f.Errors, []string{modName + ": oops"})          // mismatch errors
+	are.Equal(len(f.Dependencies), 3)                          // mismatch dependencies
+	are.Equal(f.Dependencies[0].Update, "minor")               // mismatch update
+	are.Equal(f.Dependencies[0].Status, goup.Outdated)         // mismatch status
+	are.Equal(f.Dependencies[1].Error, errup.ErrFetch.Error()) // mismatch error
+	are.Equal(f.Dependencies[1].NewVersion, "")                // unexpected new version
+	are.Equal(f.Dependencies[2].NewPath, depName+"/v2")        // mismatch new path
+	are.Equal(f.Dependencies[2].Update, "major")               // mismatch update
+	are.Equal(len(f.Directives), 1)                            // mismatch directives
+	are.Equal(f.Directives[0].Path, "go")                      // mismatch directive
+	are.Equal(f.Directives[0].Update, "minor")                 // mismatch directive update
+}
+
+func TestWriteJSON(t *testing.T) {
+	t.Parallel()
+	var (
+		are = is.New(t)
+		buf = new(strings.Builder)
+		rep = report.New("v1.0.0")
+	)
+	are.True(errors.Is(report.WriteJSON(nil, rep), errup.ErrMissing)) // expected missing writer
+	are.True(errors.Is(report.WriteJSON(buf, nil), errup.ErrMissing)) // expected missing report
+
+	rep.AddFile(modPath, modName, nil).Add(&goup.Entry{Dep: depName, Current: "v1.2.3", State: goup.UpToDate})
+	are.NoErr(report.WriteJSON(buf, rep)) // unexpected error
+
+	var res map[string]interface{}
+	are.NoErr(json.Unmarshal([]byte(buf.String()), &res))                                // invalid JSON
+	are.True(strings.Contains(buf.String(), `"status": "up-to-date"`))                   // mismatch status
+	are.Equal(res["files"].([]interface{})[0].(map[string]interface{})["path"], modPath) // mismatch path
+}
+
+type locator map[string]int
+
+func (l locator) Line(path string) int {
+	return l[path]
+}
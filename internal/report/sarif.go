@@ -0,0 +1,223 @@
+// Copyright (c) 2020 Hervé Gouchet. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package report
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"path/filepath"
+
+	errs "github.com/rvflash/goup/internal/errors"
+	"github.com/rvflash/goup/internal/semver"
+	"github.com/rvflash/goup/pkg/goup"
+)
+
+// List of SARIF rules.
+const (
+	RuleOutdatedPatch = "outdated-patch"
+	RuleOutdatedMinor = "outdated-minor"
+	RuleOutdatedMajor = "outdated-major"
+	RuleRetracted     = "retracted-version"
+	RuleDeprecated    = "deprecated-module"
+	RuleDrift         = "version-drift"
+	RuleExpectedTag   = "expected-tag"
+	RuleFetchFailure  = "fetch-failure"
+	// RuleDirective and RuleDirectiveFailure concern the go and toolchain directives.
+	RuleDirective        = "outdated-directive"
+	RuleDirectiveFailure = "directive-failure"
+)
+
+const (
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+	sarifVersion = "2.1.0"
+	toolName     = "goup"
+	toolURI      = "https://github.com/rvflash/goup"
+
+	levelError   = "error"
+	levelWarning = "warning"
+)
+
+var rules = []sarifRule{
+	newRule(RuleOutdatedPatch, "A newer patch version of the dependency is available.", levelWarning),
+	newRule(RuleOutdatedMinor, "A newer minor version of the dependency is available.", levelWarning),
+	newRule(RuleOutdatedMajor, "A newer major version of the dependency is available.", levelWarning),
+	newRule(RuleRetracted, "The dependency uses a version retracted by its authors.", levelWarning),
+	newRule(RuleDeprecated, "The dependency is deprecated by its authors.", levelWarning),
+	newRule(RuleDrift, "The dependency is required at different versions by the modules of the workspace.", levelWarning),
+	newRule(RuleExpectedTag, "The dependency must use a release tag.", levelError),
+	newRule(RuleFetchFailure, "The versions of the dependency can not be fetched.", levelError),
+	newRule(RuleDirective, "The go or toolchain directive targets an unsupported or outdated Go release.", levelWarning),
+	newRule(RuleDirectiveFailure, "The go or toolchain directive can not be updated.", levelError),
+}
+
+// WriteSARIF writes the report as a SARIF 2.1.0 log.
+// Each outdated, retracted, deprecated or failed dependency becomes a result located on its line in the go.mod file,
+// as each outdated or failed directive.
+func WriteSARIF(w io.Writer, r *Report) error {
+	if w == nil || r == nil {
+		return errs.ErrMissing
+	}
+	run := sarifRun{
+		Tool: sarifTool{Driver: sarifDriver{
+			Name:           toolName,
+			Version:        r.Version,
+			InformationURI: toolURI,
+			Rules:          rules,
+		}},
+		Results: []sarifResult{},
+	}
+	for _, f := range r.Files {
+		run.Results = appendResults(run.Results, f.Path, f.Dependencies)
+		run.Results = appendResults(run.Results, f.Path, f.Directives)
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(sarifLog{
+		Schema:  sarifSchema,
+		Version: sarifVersion,
+		Runs:    []sarifRun{run},
+	})
+}
+
+func appendResults(res []sarifResult, path string, deps []*Dependency) []sarifResult {
+	for _, d := range deps {
+		id, ok := d.rule()
+		if !ok {
+			continue
+		}
+		res = append(res, sarifResult{
+			RuleID:    id,
+			Level:     d.level(),
+			Message:   sarifMessage{Text: d.Message},
+			Locations: []sarifLocation{newLocation(path, d.Line)},
+		})
+	}
+	return res
+}
+
+func (d *Dependency) rule() (string, bool) {
+	if d.directive {
+		switch d.Status {
+		case goup.Outdated:
+			return RuleDirective, true
+		case goup.Failed:
+			return RuleDirectiveFailure, true
+		default:
+			return "", false
+		}
+	}
+	switch d.Status {
+	case goup.Outdated:
+		switch d.Update {
+		case semver.Major:
+			return RuleOutdatedMajor, true
+		case semver.Minor:
+			return RuleOutdatedMinor, true
+		default:
+			return RuleOutdatedPatch, true
+		}
+	case goup.MajorAvailable:
+		return RuleOutdatedMajor, true
+	case goup.Retracted:
+		return RuleRetracted, true
+	case goup.Deprecated:
+		return RuleDeprecated, true
+	case goup.Drifted:
+		return RuleDrift, true
+	case goup.Failed:
+		if errors.Is(d.err, errs.ErrExpectedTag) {
+			return RuleExpectedTag, true
+		}
+		return RuleFetchFailure, true
+	default:
+		return "", false
+	}
+}
+
+func (d *Dependency) level() string {
+	if d.Status == goup.Failed {
+		return levelError
+	}
+	return levelWarning
+}
+
+func newRule(id, desc, level string) sarifRule {
+	return sarifRule{
+		ID:                   id,
+		ShortDescription:     sarifMessage{Text: desc},
+		DefaultConfiguration: sarifConfiguration{Level: level},
+	}
+}
+
+func newLocation(path string, line int) sarifLocation {
+	loc := sarifLocation{PhysicalLocation: sarifPhysicalLocation{
+		ArtifactLocation: sarifArtifactLocation{URI: filepath.ToSlash(path)},
+	}}
+	if line > 0 {
+		loc.PhysicalLocation.Region = &sarifRegion{StartLine: line}
+	}
+	return loc
+}
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	Version        string      `json:"version,omitempty"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID                   string             `json:"id"`
+	ShortDescription     sarifMessage       `json:"shortDescription"`
+	DefaultConfiguration sarifConfiguration `json:"defaultConfiguration"`
+}
+
+type sarifConfiguration struct {
+	Level string `json:"level"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine int `json:"startLine"`
+}
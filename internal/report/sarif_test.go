@@ -0,0 +1,96 @@
+// Copyright (c) 2020 Hervé Gouchet. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package report_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/matryer/is"
+	errup "github.com/rvflash/goup/internal/errors"
+	"github.com/rvflash/goup/internal/report"
+	"github.com/rvflash/goup/pkg/goup"
+)
+
+func TestWriteSARIF(t *testing.T) {
+	t.Parallel()
+	var (
+		are = is.New(t)
+		buf = new(strings.Builder)
+		rep = report.New("v1.0.0")
+		loc = locator{
+			depName + "/a": 3, depName + "/b": 4, depName + "/c": 5, depName + "/r": 6, depName + "/x": 7, depName + "/w": 8,
+			depName + "/d": 9, depName + "/e": 10, depName + "/m": 11, "go": 12, "toolchain": 13,
+		}
+	)
+	are.True(errors.Is(report.WriteSARIF(nil, rep), errup.ErrMissing)) // expected missing writer
+	are.True(errors.Is(report.WriteSARIF(buf, nil), errup.ErrMissing)) // expected missing report
+
+	f := rep.AddFile(modPath, modName, loc)
+	f.Add(&goup.Entry{Dep: depName + "/a", Current: "v1.2.3", Proposed: "v1.2.4", State: goup.Outdated})
+	f.Add(&goup.Entry{Dep: depName + "/b", Current: "v1.2.3", Proposed: "v1.3.0", State: goup.Outdated})
+	f.Add(&goup.Entry{Dep: depName + "/c", Current: "v1.2.3", Proposed: "v2.0.0", State: goup.Outdated})
+	f.Add(&goup.Entry{Dep: depName + "/r", Current: "v1.2.3", State: goup.Retracted})
+	f.Add(&goup.Entry{Dep: depName + "/x", Current: "v1.2.3", State: goup.Deprecated})
+	f.Add(&goup.Entry{Dep: depName + "/w", Current: "v1.2.3", State: goup.Drifted})
+	f.Add(&goup.Entry{Dep: depName + "/d", Current: "v1.2.3-rc", State: goup.Failed, Cause: errup.ErrExpectedTag})
+	f.Add(&goup.Entry{Dep: depName + "/e", Current: "v1.2.3", State: goup.Failed, Cause: errup.ErrFetch})
+	f.Add(&goup.Entry{Dep: depName + "/f", Current: "v1.2.3", State: goup.UpToDate})
+	f.Add(&goup.Entry{
+		Dep: depName + "/m", Current: "v1.2.3", Proposed: "v2.0.0", ProposedPath: depName + "/m/v2", State: goup.MajorAvailable,
+	})
+	f.Add(&goup.Entry{Dep: "go", Current: "1.19", Proposed: "1.22.0", State: goup.Outdated, IsDirective: true})
+	f.Add(&goup.Entry{Dep: "toolchain", Current: "go1.22.0", State: goup.Failed, Cause: errup.ErrMod, IsDirective: true})
+	f.Add(&goup.Entry{Dep: "toolchain", Current: "go1.22.0", State: goup.UpToDate, IsDirective: true})
+	are.NoErr(report.WriteSARIF(buf, rep)) // unexpected error
+
+	var res struct {
+		Version string `json:"version"`
+		Runs    []struct {
+			Results []struct {
+				RuleID    string `json:"ruleId"`
+				Level     string `json:"level"`
+				Locations []struct {
+					PhysicalLocation struct {
+						ArtifactLocation struct {
+							URI string `json:"uri"`
+						} `json:"artifactLocation"`
+						Region struct {
+							StartLine int `json:"startLine"`
+						} `json:"region"`
+					} `json:"physicalLocation"`
+				} `json:"locations"`
+			} `json:"results"`
+		} `json:"runs"`
+	}
+	are.NoErr(json.Unmarshal([]byte(buf.String()), &res)) // invalid JSON
+	are.Equal(res.Version, "2.1.0")                       // mismatch version
+	are.Equal(len(res.Runs), 1)                           // mismatch runs
+	are.Equal(len(res.Runs[0].Results), 11)               // mismatch results
+
+	exp := []string{
+		report.RuleOutdatedPatch,
+		report.RuleOutdatedMinor,
+		report.RuleOutdatedMajor,
+		report.RuleRetracted,
+		report.RuleDeprecated,
+		report.RuleDrift,
+		report.RuleExpectedTag,
+		report.RuleFetchFailure,
+		report.RuleOutdatedMajor,
+		report.RuleDirective,
+		report.RuleDirectiveFailure,
+	}
+	for k, r := range res.Runs[0].Results {
+		are.Equal(r.RuleID, exp[k])                                               // mismatch rule
+		are.Equal(r.Locations[0].PhysicalLocation.ArtifactLocation.URI, modPath)  // mismatch uri
+		are.Equal(r.Locations[0].PhysicalLocation.Region.StartLine, k+3)          // mismatch line
+		are.Equal(r.Level == "error", k > 5 && k < 8 || k == 10)                  // mismatch level
+		are.True(strings.Contains(buf.String(), fmt.Sprintf(`"id": %q`, exp[k]))) // missing rule
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"sort"
 
+	"golang.org/x/mod/module"
 	"golang.org/x/mod/semver"
 )
 
@@ -56,15 +57,46 @@ func LatestPatch(majorMinor string, versions Tags) Tag {
 	return latest
 }
 
+// List of kinds of update between two versions.
+const (
+	Major      = "major"
+	Minor      = "minor"
+	Patch      = "patch"
+	Prerelease = "prerelease"
+	Pseudo     = "pseudo"
+)
+
+// Kind returns the kind of update required to go from the version v to w.
+// An update to a pseudo-version or a prerelease is qualified as such, whatever the versions.
+// It returns an empty string if w is not greater than v.
+func Kind(v, w Tag) string {
+	if v == nil || w == nil || Compare(v, w) >= 0 {
+		return ""
+	}
+	switch {
+	case module.IsPseudoVersion(Base(w)):
+		return Pseudo
+	case w.Prerelease() != "":
+		return Prerelease
+	case v.Major() != w.Major():
+		return Major
+	case v.MajorMinor() != w.MajorMinor():
+		return Minor
+	default:
+		return Patch
+	}
+}
+
 // Compare returns an integer comparing two versions according to
 // semantic version precedence.
 // The result will be 0 if v == w, -1 if v < w, or +1 if v > w.
 //
 // An invalid semantic version string is considered less than a valid one.
 // All invalid semantic version strings compare equal to each other.
+// The directory prefix of the tags of a nested module is ignored.
 func Compare(v, w fmt.Stringer) int {
 	if v == nil || w == nil {
 		return 0
 	}
-	return semver.Compare(v.String(), w.String())
+	return semver.Compare(Base(v), Base(w))
 }
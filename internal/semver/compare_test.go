@@ -31,6 +31,7 @@ func TestCompare(t *testing.T) {
 			"<":       {v: tag("v0.2.3"), w: tag("v1.2.2"), out: -1},
 			"<=":      {v: tag("v0.2.3-12"), w: tag("v0.2.3"), out: -1},
 			"=":       {v: tag("v0.2.3+12"), w: tag("v0.2.3")},
+			"prefix":  {v: tag("gopls/v0.16.0"), w: tag("gopls/v0.15.3"), out: 1},
 		}
 	)
 	for name, ts := range dt {
@@ -43,6 +44,38 @@ func TestCompare(t *testing.T) {
 	}
 }
 
+func TestKind(t *testing.T) {
+	t.Parallel()
+	var (
+		are = is.New(t)
+		dt  = map[string]struct {
+			v, w semver.Tag
+			out  string
+		}{
+			"default":       {},
+			"same":          {v: v0, w: v0},
+			"downgrade":     {v: v0, w: v2},
+			"patch":         {v: v2, w: v0, out: semver.Patch},
+			"prerelease":    {v: v1, w: v2, out: semver.Patch},
+			"minor":         {v: semver.New("v1.1.0"), w: v5, out: semver.Minor},
+			"major":         {v: v4, w: v5, out: semver.Major},
+			"to prerelease": {v: v2, w: semver.New("v2.3.0-rc.1"), out: semver.Prerelease},
+			"to pseudo": {
+				v:   v2,
+				w:   semver.New("v2.2.13-0.20200121190230-accd165b1659"),
+				out: semver.Pseudo,
+			},
+		}
+	)
+	for name, ts := range dt {
+		tt := ts
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			are.Equal(semver.Kind(tt.v, tt.w), tt.out) // mismatch result
+		})
+	}
+}
+
 func TestLatest(t *testing.T) {
 	t.Parallel()
 	var (
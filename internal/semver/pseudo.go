@@ -0,0 +1,76 @@
+// Copyright (c) 2020 Hervé Gouchet. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package semver
+
+import (
+	"fmt"
+	"time"
+
+	"golang.org/x/mod/module"
+)
+
+// PseudoVersion is a decoded pseudo-version, like v0.2.1-0.20200121190230-accd165b1659.
+// It references a commit without tag by its time and its revision identifier.
+type PseudoVersion struct {
+	// Base is the release on which the commit is based, like v0.2.0. It is empty without such release.
+	Base string
+	// Time is the UTC time of the commit.
+	Time time.Time
+	// Rev is the short revision identifier of the commit.
+	Rev string
+}
+
+// ParsePseudo decodes the pseudo-version.
+// It returns false if the version is not a pseudo-version.
+func ParsePseudo(v fmt.Stringer) (PseudoVersion, bool) {
+	s := Base(v)
+	if !module.IsPseudoVersion(s) {
+		return PseudoVersion{}, false
+	}
+	base, err := module.PseudoVersionBase(s)
+	if err != nil {
+		return PseudoVersion{}, false
+	}
+	t, err := module.PseudoVersionTime(s)
+	if err != nil {
+		return PseudoVersion{}, false
+	}
+	rev, err := module.PseudoVersionRev(s)
+	if err != nil {
+		return PseudoVersion{}, false
+	}
+	return PseudoVersion{Base: base, Time: t, Rev: rev}, true
+}
+
+// Days returns the age of the commit in days at this time.
+func (p PseudoVersion) Days(now time.Time) int {
+	if p.Time.IsZero() || now.Before(p.Time) {
+		return 0
+	}
+	return int(now.Sub(p.Time) / (24 * time.Hour))
+}
+
+// IsCommit returns true if the revision identifier matches the commit of the pseudo-version.
+// The pseudo-versions only keep the first twelve characters of the commit hash.
+func (p PseudoVersion) IsCommit(rev string) bool {
+	n := len(p.Rev)
+	if n == 0 || len(rev) < n {
+		return false
+	}
+	return rev[:n] == p.Rev
+}
+
+// NewPseudo returns the pseudo-version of the commit, based on the given release if any.
+// Without release, the pseudo-version is based on the major, like v2.0.0, and on v0.0.0 without major.
+func NewPseudo(major, older string, t time.Time, rev string) string {
+	const revLen = 12
+	if len(rev) > revLen {
+		rev = rev[:revLen]
+	}
+	if major == "v0" || major == "v1" {
+		major = ""
+	}
+	return module.PseudoVersion(major, older, t.UTC(), rev)
+}
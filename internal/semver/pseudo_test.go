@@ -0,0 +1,95 @@
+// Copyright (c) 2020 Hervé Gouchet. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package semver_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/matryer/is"
+	"github.com/rvflash/goup/internal/semver"
+)
+
+func TestParsePseudo(t *testing.T) {
+	t.Parallel()
+	var (
+		are = is.New(t)
+		ts  = time.Date(2020, 1, 21, 19, 2, 30, 0, time.UTC)
+		dt  = map[string]struct {
+			in  string
+			out semver.PseudoVersion
+			ok  bool
+		}{
+			"default":  {},
+			"release":  {in: "v0.2.1"},
+			"no base":  {in: "v0.0.0-20200121190230-accd165b1659", out: semver.PseudoVersion{Time: ts, Rev: "accd165b1659"}, ok: true},
+			"patch":    {in: "v0.2.1-0.20200121190230-accd165b1659", out: semver.PseudoVersion{Base: "v0.2.0", Time: ts, Rev: "accd165b1659"}, ok: true},
+			"pre":      {in: "v1.3.0-rc.1.0.20200121190230-accd165b1659", out: semver.PseudoVersion{Base: "v1.3.0-rc.1", Time: ts, Rev: "accd165b1659"}, ok: true},
+			"nested":   {in: "gopls/v0.2.1-0.20200121190230-accd165b1659", out: semver.PseudoVersion{Base: "v0.2.0", Time: ts, Rev: "accd165b1659"}, ok: true},
+			"major v2": {in: "v2.0.0-20200121190230-accd165b1659", out: semver.PseudoVersion{Time: ts, Rev: "accd165b1659"}, ok: true},
+		}
+	)
+	for name, ts := range dt {
+		tt := ts
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			out, ok := semver.ParsePseudo(semver.New(tt.in))
+			are.Equal(ok, tt.ok)   // mismatch result
+			are.Equal(out, tt.out) // mismatch pseudo-version
+		})
+	}
+}
+
+func TestPseudoVersion_Days(t *testing.T) {
+	t.Parallel()
+	var (
+		are = is.New(t)
+		ts  = time.Date(2020, 1, 21, 19, 2, 30, 0, time.UTC)
+		p   = semver.PseudoVersion{Time: ts}
+	)
+	are.Equal(semver.PseudoVersion{}.Days(ts), 0) // mismatch without time
+	are.Equal(p.Days(ts.Add(-time.Hour)), 0)      // mismatch in the future
+	are.Equal(p.Days(ts.Add(47*time.Hour)), 1)    // mismatch day
+	are.Equal(p.Days(ts.AddDate(0, 0, 366)), 366) // mismatch year
+}
+
+func TestPseudoVersion_IsCommit(t *testing.T) {
+	t.Parallel()
+	var (
+		are = is.New(t)
+		p   = semver.PseudoVersion{Rev: "accd165b1659"}
+	)
+	are.True(p.IsCommit("accd165b1659b4e2d5d0d2c7a2b1c2b3a4d5e6f7"))  // mismatch full hash
+	are.True(!p.IsCommit("accd165"))                                  // mismatch short hash
+	are.True(!p.IsCommit("bccd165b1659b4e2d5d0d2c7a2b1c2b3a4d5e6f7")) // mismatch other commit
+	are.True(!semver.PseudoVersion{}.IsCommit(""))                    // mismatch without commit
+}
+
+func TestNewPseudo(t *testing.T) {
+	t.Parallel()
+	var (
+		are = is.New(t)
+		ts  = time.Date(2020, 1, 21, 19, 2, 30, 0, time.UTC)
+		rev = "accd165b1659b4e2d5d0d2c7a2b1c2b3a4d5e6f7"
+		dt  = map[string]struct {
+			major, older string
+			out          string
+		}{
+			"default":  {out: "v0.0.0-20200121190230-accd165b1659"},
+			"v1":       {major: "v1", out: "v0.0.0-20200121190230-accd165b1659"},
+			"v2":       {major: "v2", out: "v2.0.0-20200121190230-accd165b1659"},
+			"release":  {major: "v0", older: "v0.2.0", out: "v0.2.1-0.20200121190230-accd165b1659"},
+			"pre":      {major: "v1", older: "v1.3.0-rc.1", out: "v1.3.0-rc.1.0.20200121190230-accd165b1659"},
+			"major v3": {major: "v3", older: "v3.1.2", out: "v3.1.3-0.20200121190230-accd165b1659"},
+		}
+	)
+	for name, ts2 := range dt {
+		tt := ts2
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			are.Equal(semver.NewPseudo(tt.major, tt.older, ts, rev), tt.out) // mismatch pseudo-version
+		})
+	}
+}
@@ -0,0 +1,108 @@
+// Copyright (c) 2020 Hervé Gouchet. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package semver
+
+import (
+	"fmt"
+	"strings"
+
+	"golang.org/x/mod/semver"
+)
+
+// Range is a list of constraints, all of them must be satisfied by a version.
+type Range []constraint
+
+// List of supported operators, the longest first.
+var operators = []string{">=", "<=", "!=", ">", "<", "="}
+
+// ParseRange parses a list of constraints separated by spaces or commas, like ">=v1.2.0, <v2".
+// The leading v of each version is optional.
+func ParseRange(s string) (Range, error) {
+	var r Range
+	for _, f := range strings.FieldsFunc(s, func(r rune) bool {
+		return r == ',' || r == ' '
+	}) {
+		c, err := parseConstraint(f)
+		if err != nil {
+			return nil, err
+		}
+		r = append(r, c)
+	}
+	return r, nil
+}
+
+// Match returns true if the version satisfies all the constraints.
+func (r Range) Match(v Tag) bool {
+	if v == nil || !v.IsValid() {
+		return false
+	}
+	for _, c := range r {
+		if !c.match(v.Canonical()) {
+			return false
+		}
+	}
+	return true
+}
+
+// Filter returns the versions matching the range.
+func (r Range) Filter(versions Tags) Tags {
+	if len(r) == 0 {
+		return versions
+	}
+	res := make(Tags, 0, len(versions))
+	for _, v := range versions {
+		if r.Match(v) {
+			res = append(res, v)
+		}
+	}
+	return res
+}
+
+type constraint struct {
+	op      string
+	version string
+}
+
+func parseConstraint(s string) (constraint, error) {
+	var c constraint
+	for _, op := range operators {
+		if strings.HasPrefix(s, op) {
+			c.op = op
+			break
+		}
+	}
+	if c.op == "" {
+		// Without operator, the version must be the same.
+		c.op = "="
+	} else {
+		s = s[len(c.op):]
+	}
+	if !strings.HasPrefix(s, "v") {
+		s = "v" + s
+	}
+	if !semver.IsValid(s) {
+		return c, fmt.Errorf("invalid version range: %q", s)
+	}
+	c.version = s
+	return c, nil
+}
+
+func (c constraint) match(v string) bool {
+	n := semver.Compare(v, c.version)
+	switch c.op {
+	case ">=":
+		return n >= 0
+	case "<=":
+		return n <= 0
+	case "!=":
+		return n != 0
+	case ">":
+		return n > 0
+	case "<":
+		return n < 0
+	default:
+		return n == 0
+	}
+}
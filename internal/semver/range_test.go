@@ -0,0 +1,43 @@
+// Copyright (c) 2020 Hervé Gouchet. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package semver_test
+
+import (
+	"testing"
+
+	"github.com/matryer/is"
+	"github.com/rvflash/goup/internal/semver"
+)
+
+func TestParseRange(t *testing.T) {
+	t.Parallel()
+	var (
+		are = is.New(t)
+		dt  = map[string]struct {
+			in   string
+			fail bool
+			out  semver.Tags
+		}{
+			"default":  {out: tags()},
+			"invalid":  {in: ">=vx.2", fail: true},
+			"exact":    {in: "1.2.13", out: semver.Tags{v5}},
+			"lower":    {in: "<v1", out: semver.Tags{v4}},
+			"between":  {in: ">=v1.2.13, <v2", out: semver.Tags{v5}},
+			"spaces":   {in: ">v1 <=v2.2.12 !=v2.2.12-beta", out: semver.Tags{v2, v3, v5}},
+			"no match": {in: ">v3", out: semver.Tags{}},
+		}
+	)
+	for name, ts := range dt {
+		tt := ts
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			r, err := semver.ParseRange(tt.in)
+			are.Equal(err != nil, tt.fail) // mismatch error
+			if !tt.fail {
+				are.Equal(r.Filter(tags()), tt.out) // mismatch result
+			}
+		})
+	}
+}
@@ -37,6 +37,27 @@ func (t Tags) Not(versions ...fmt.Stringer) Tags {
 	return t2
 }
 
+// Dir returns the tags of the module living in this subdirectory of its repository.
+// The tags of a nested module are prefixed by its directory, like "gopls/v0.16.0".
+// With an empty directory, only the tags without prefix are kept: those of the root module.
+func (t Tags) Dir(dir string) Tags {
+	dir = strings.Trim(dir, "/")
+	res := make(Tags, 0, len(t))
+	for _, v := range t {
+		if v == nil {
+			continue
+		}
+		var prefix string
+		if s := v.String(); strings.Contains(s, "/") {
+			prefix = s[:strings.LastIndex(s, "/")]
+		}
+		if prefix == dir {
+			res = append(res, v)
+		}
+	}
+	return res
+}
+
 // Len implements the sort interface.
 func (t Tags) Len() int {
 	return len(t)
@@ -82,6 +103,15 @@ func New(version string) *Version {
 	return v
 }
 
+// Base returns the version without the directory prefix of the tags of a nested module.
+// For example, v0.16.0 for gopls/v0.16.0.
+func Base(v fmt.Stringer) string {
+	if v == nil {
+		return ""
+	}
+	return trim(v.String())
+}
+
 func trim(s string) string {
 	if p := strings.LastIndex(s, "/"); p > -1 {
 		return s[p+1:]
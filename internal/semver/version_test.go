@@ -121,3 +121,46 @@ func TestTags_Not(t *testing.T) {
 	out = out.Not(semver.New("v1.2.13"))
 	are.Equal(4, len(out)) // mismatch result
 }
+
+func TestTags_Dir(t *testing.T) {
+	t.Parallel()
+	var (
+		are = is.New(t)
+		in  = semver.Tags{
+			semver.New("v0.24.0"),
+			semver.New("gopls/v0.16.0"),
+			semver.New("gopls/v0.16.1"),
+			semver.New("cmd/gopls/v0.1.0"),
+			nil,
+		}
+		dt = map[string]struct {
+			in  string
+			out []string
+		}{
+			"root":   {out: []string{"v0.24.0"}},
+			"nested": {in: "gopls", out: []string{"gopls/v0.16.0", "gopls/v0.16.1"}},
+			"slash":  {in: "/cmd/gopls/", out: []string{"cmd/gopls/v0.1.0"}},
+			"none":   {in: "unknown", out: []string{}},
+		}
+	)
+	for name, ts := range dt {
+		tt := ts
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			res := in.Dir(tt.in)
+			out := make([]string, len(res))
+			for k, v := range res {
+				out[k] = v.String()
+			}
+			are.Equal(out, tt.out) // mismatch result
+		})
+	}
+}
+
+func TestBase(t *testing.T) {
+	t.Parallel()
+	are := is.New(t)
+	are.Equal(semver.Base(nil), "")                                // mismatch default
+	are.Equal(semver.Base(semver.New("v0.24.0")), "v0.24.0")       // mismatch root
+	are.Equal(semver.Base(semver.New("gopls/v0.16.0")), "v0.16.0") // mismatch nested
+}
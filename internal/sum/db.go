@@ -0,0 +1,242 @@
+// Copyright (c) 2020 Hervé Gouchet. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package sum
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	errs "github.com/rvflash/goup/internal/errors"
+	"github.com/rvflash/goup/internal/vcs"
+
+	"golang.org/x/mod/module"
+	"golang.org/x/mod/sumdb"
+	"golang.org/x/mod/sumdb/note"
+)
+
+const (
+	// DBName is the name used to prefix the errors of the checksum database.
+	DBName = "sumdb"
+	// DefaultDB is the checksum database used without GOSUMDB.
+	DefaultDB = "sum.golang.org"
+	// Off is the GOSUMDB value disabling the checksum database.
+	Off = "off"
+
+	defaultKey = "sum.golang.org+033de0ae+Ac4zctda0e5eza+HJyk9SxEdh+s3Ux18htTTAD8OuAn8"
+	keyFile    = "key"
+	latestFile = "/latest"
+	plus       = "+"
+)
+
+// DB is a checksum database, like sum.golang.org.
+// The records are authenticated by the tiled transparency log of the database,
+// its signed tree head is only kept in memory, for the time of the run.
+type DB struct {
+	ops     *ops
+	noSumDB string
+}
+
+// NewDB returns a new instance of DB.
+// goSumDB is a GOSUMDB value, like sum.golang.org or "name+key https://sum.example.com",
+// and noSumDB a comma-separated list of glob patterns (GONOSUMDB) matching the module paths to not verify.
+// With GOSUMDB=off, no database is returned.
+func NewDB(client vcs.ClientChooser, auth vcs.BasicAuthentifier, goSumDB, noSumDB string) (*DB, error) {
+	if client == nil {
+		return nil, errs.ErrSystem
+	}
+	key, rawURL, err := parseDB(goSumDB)
+	if err != nil || key == "" {
+		return nil, err
+	}
+	v, err := note.NewVerifier(key)
+	if err != nil {
+		return nil, vcs.Errorf(DBName, errs.ErrSystem, err)
+	}
+	o := &ops{
+		name: v.Name(),
+		key:  []byte(key),
+		url:  rawURL,
+		http: client,
+		auth: auth,
+		mem: &memory{
+			config: make(map[string][]byte),
+			cache:  make(map[string][]byte),
+		},
+	}
+	return &DB{ops: o, noSumDB: noSumDB}, nil
+}
+
+// parseDB returns the verifier key and the URL of the checksum database described by this GOSUMDB value.
+// The key of sum.golang.org is known, so its name is enough.
+// Without URL, the database is served by https on its name. With GOSUMDB=off, the key is empty.
+func parseDB(goSumDB string) (key, rawURL string, err error) {
+	f := strings.Fields(goSumDB)
+	switch {
+	case len(f) == 0:
+		f = []string{DefaultDB}
+	case f[0] == Off:
+		return "", "", nil
+	case len(f) > 2:
+		return "", "", vcs.Errorf(DBName, errs.ErrSystem, "invalid GOSUMDB: "+goSumDB)
+	}
+	key = f[0]
+	if !strings.Contains(key, plus) {
+		if key != DefaultDB {
+			return "", "", vcs.Errorf(DBName, errs.ErrSystem, "unknown GOSUMDB key: "+key)
+		}
+		key = defaultKey
+	}
+	name, _, _ := strings.Cut(key, plus)
+	rawURL = vcs.URLScheme(vcs.HTTPS) + name
+	if len(f) == 2 {
+		rawURL = f[1]
+	}
+	return key, strings.TrimRight(rawURL, "/"), nil
+}
+
+// Name returns the name of the checksum database.
+func (db *DB) Name() string {
+	if db == nil {
+		return ""
+	}
+	return db.ops.name
+}
+
+// CanVerify returns true if the module path does not match GONOSUMDB.
+// As with the go command, the patterns also match the prefixes of the path.
+func (db *DB) CanVerify(path string) bool {
+	return db != nil && path != "" && !module.MatchPrefixPatterns(db.noSumDB, path)
+}
+
+// Lookup returns the go.sum lines of this module version, as authenticated by the checksum database.
+// As with the go.sum file, the version of a go.mod file is suffixed by /go.mod.
+// It returns errors.ErrChecksum if the database does not know the version
+// and errors.ErrSystem if the module path matches GONOSUMDB.
+func (db *DB) Lookup(ctx context.Context, path, version string) ([]string, error) {
+	if !db.CanVerify(path) || ctx == nil {
+		return nil, errs.ErrSystem
+	}
+	// Each lookup has its own client, so its remote requests are canceled with its context.
+	// The signed tree head and the tiles already read are shared in memory.
+	lines, err := sumdb.NewClient(db.ops.withContext(ctx)).Lookup(path, version)
+	switch {
+	case ctx.Err() != nil:
+		return nil, vcs.Errorf(DBName, errs.ErrFetch, ctx.Err())
+	case err != nil:
+		return nil, vcs.Errorf(DBName, errs.ErrChecksum, err)
+	case len(lines) == 0:
+		return nil, vcs.Errorf(DBName, errs.ErrChecksum, path+" "+version)
+	}
+	return lines, nil
+}
+
+// Verify checks that the checksum database knows this hash of the module version.
+func (db *DB) Verify(ctx context.Context, path, version, hash string) error {
+	lines, err := db.Lookup(ctx, path, version)
+	if err != nil {
+		return err
+	}
+	want := path + " " + version + " " + hash
+	for _, line := range lines {
+		if line == want {
+			return nil
+		}
+	}
+	return vcs.Errorf(DBName, errs.ErrChecksum, fmt.Sprintf("%s %s: %s, expected %s", path, version, hash, lines))
+}
+
+// ops implements the sumdb.ClientOps interface.
+// The remote requests use its context, the configuration and the tiles are kept in memory.
+type ops struct {
+	ctx       context.Context
+	name, url string
+	key       []byte
+	http      vcs.ClientChooser
+	auth      vcs.BasicAuthentifier
+	mem       *memory
+}
+
+// memory is the storage of the configuration and the tiles, shared by the lookups.
+type memory struct {
+	mu     sync.Mutex
+	config map[string][]byte
+	cache  map[string][]byte
+}
+
+// withContext returns a copy of the ops sending its remote requests with this context.
+func (o ops) withContext(ctx context.Context) *ops {
+	o.ctx = ctx
+	return &o
+}
+
+// ReadRemote implements the sumdb.ClientOps interface.
+func (o *ops) ReadRemote(path string) ([]byte, error) {
+	body, err := vcs.Get(o.ctx, o.http, o.auth, DBName, o.url+path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = body.Close() }()
+
+	res, err := io.ReadAll(body)
+	if err != nil {
+		return nil, vcs.Errorf(DBName, errs.ErrFetch, err)
+	}
+	return res, nil
+}
+
+// ReadConfig implements the sumdb.ClientOps interface.
+// Without known signed tree head, the client starts with an empty one.
+func (o *ops) ReadConfig(file string) ([]byte, error) {
+	if file == keyFile {
+		return o.key, nil
+	}
+	if file != o.name+latestFile {
+		return nil, os.ErrNotExist
+	}
+	o.mem.mu.Lock()
+	defer o.mem.mu.Unlock()
+	return o.mem.config[file], nil
+}
+
+// WriteConfig implements the sumdb.ClientOps interface.
+func (o *ops) WriteConfig(file string, old, cur []byte) error {
+	o.mem.mu.Lock()
+	defer o.mem.mu.Unlock()
+	if !bytes.Equal(o.mem.config[file], old) {
+		return sumdb.ErrWriteConflict
+	}
+	o.mem.config[file] = cur
+	return nil
+}
+
+// ReadCache implements the sumdb.ClientOps interface.
+func (o *ops) ReadCache(file string) ([]byte, error) {
+	o.mem.mu.Lock()
+	defer o.mem.mu.Unlock()
+	b, ok := o.mem.cache[file]
+	if !ok {
+		return nil, os.ErrNotExist
+	}
+	return b, nil
+}
+
+// WriteCache implements the sumdb.ClientOps interface.
+func (o *ops) WriteCache(file string, data []byte) {
+	o.mem.mu.Lock()
+	defer o.mem.mu.Unlock()
+	o.mem.cache[file] = data
+}
+
+// Log implements the sumdb.ClientOps interface.
+func (o *ops) Log(string) {}
+
+// SecurityError implements the sumdb.ClientOps interface.
+// The client returns sumdb.ErrSecurity on the lookup, reported as a checksum mismatch.
+func (o *ops) SecurityError(string) {}
@@ -0,0 +1,151 @@
+// Copyright (c) 2020 Hervé Gouchet. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package sum_test
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/matryer/is"
+	errup "github.com/rvflash/goup/internal/errors"
+	"github.com/rvflash/goup/internal/sum"
+	"github.com/rvflash/goup/internal/vcs"
+
+	"golang.org/x/mod/sumdb"
+	"golang.org/x/mod/sumdb/note"
+)
+
+const (
+	dbName  = "sum.example.test"
+	zipHash = "h1:zip120="
+	modHash = "h1:mod120="
+)
+
+// newDB returns a checksum database served by a local test server, only knowing the v1.2.0 of the package.
+func newDB(t *testing.T, noSumDB string) *sum.DB {
+	t.Helper()
+	skey, vkey, err := note.GenerateKey(rand.Reader, dbName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := httptest.NewServer(sumdb.NewServer(sumdb.NewTestServer(skey, func(path, version string) ([]byte, error) {
+		if path != pkgName || version != "v1.2.0" {
+			return nil, os.ErrNotExist
+		}
+		return []byte(path + " " + version + " " + zipHash + "\n" +
+			path + " " + version + sum.ModSuffix + " " + modHash + "\n"), nil
+	})))
+	t.Cleanup(srv.Close)
+	db, err := sum.NewDB(vcs.NewHTTPClient(time.Second, ""), nil, vkey+" "+srv.URL, noSumDB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+func TestNewDB(t *testing.T) {
+	t.Parallel()
+	var (
+		are = is.New(t)
+		cli = vcs.NewHTTPClient(time.Second, "")
+		dt  = map[string]struct {
+			in   string
+			name string
+			err  error
+		}{
+			"default":     {name: sum.DefaultDB},
+			"by name":     {in: sum.DefaultDB, name: sum.DefaultDB},
+			"off":         {in: sum.Off},
+			"unknown":     {in: "sum.example.com", err: errup.ErrSystem},
+			"invalid key": {in: "sum.example.com+bad", err: errup.ErrSystem},
+			"too long":    {in: sum.DefaultDB + " https://a https://b", err: errup.ErrSystem},
+		}
+	)
+	_, err := sum.NewDB(nil, nil, "", "")
+	are.True(errors.Is(err, errup.ErrSystem)) // mismatch error
+	for name, ts := range dt {
+		tt := ts
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			db, err := sum.NewDB(cli, nil, tt.in, "")
+			are.True(errors.Is(err, tt.err)) // mismatch error
+			are.Equal(db.Name(), tt.name)    // mismatch name
+		})
+	}
+}
+
+func TestDB_CanVerify(t *testing.T) {
+	t.Parallel()
+	are := is.New(t)
+	db := newDB(t, "example.com/private,*.corp.lan")
+	are.True(!(*sum.DB)(nil).CanVerify(pkgName))       // no database
+	are.True(db.CanVerify(pkgName))                    // mismatch public
+	are.True(!db.CanVerify("example.com/private/pkg")) // mismatch prefix
+	are.True(!db.CanVerify("git.corp.lan/group/pkg"))  // mismatch glob
+	_, err := db.Lookup(context.Background(), "example.com/private", "v1.0.0")
+	are.True(errors.Is(err, errup.ErrSystem)) // mismatch error
+}
+
+func TestDB_Lookup(t *testing.T) {
+	t.Parallel()
+	var (
+		are = is.New(t)
+		db  = newDB(t, "")
+		ctx = context.Background()
+	)
+	res, err := db.Lookup(ctx, pkgName, "v1.2.0")
+	are.NoErr(err)                                           // unexpected error
+	are.Equal(res, []string{pkgName + " v1.2.0 " + zipHash}) // mismatch zip lines
+	res, err = db.Lookup(ctx, pkgName, "v1.2.0"+sum.ModSuffix)
+	are.NoErr(err)                                                                // unexpected error
+	are.Equal(res, []string{pkgName + " v1.2.0" + sum.ModSuffix + " " + modHash}) // mismatch go.mod lines
+	_, err = db.Lookup(ctx, pkgName, "v1.3.0")
+	are.True(errors.Is(err, errup.ErrChecksum)) // unknown version
+}
+
+func TestDB_Lookup_Timeout(t *testing.T) {
+	t.Parallel()
+	var (
+		are      = is.New(t)
+		canceled = make(chan struct{}, 1)
+	)
+	_, vkey, err := note.GenerateKey(rand.Reader, dbName)
+	are.NoErr(err) // unexpected error
+	srv := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+		canceled <- struct{}{}
+	}))
+	t.Cleanup(srv.Close)
+	db, err := sum.NewDB(vcs.NewHTTPClient(time.Minute, ""), nil, vkey+" "+srv.URL, "")
+	are.NoErr(err) // unexpected error
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err = db.Lookup(ctx, pkgName, "v1.2.0")
+	are.True(errors.Is(err, errup.ErrFetch)) // mismatch error
+	select {
+	case <-canceled:
+	case <-time.After(time.Second):
+		t.Fatal("the request of the lookup is not canceled")
+	}
+}
+
+func TestDB_Verify(t *testing.T) {
+	t.Parallel()
+	var (
+		are = is.New(t)
+		db  = newDB(t, "")
+		ctx = context.Background()
+	)
+	are.NoErr(db.Verify(ctx, pkgName, "v1.2.0", zipHash))                                  // unexpected error
+	are.NoErr(db.Verify(ctx, pkgName, "v1.2.0"+sum.ModSuffix, modHash))                    // unexpected error
+	are.True(errors.Is(db.Verify(ctx, pkgName, "v1.2.0", "h1:other="), errup.ErrChecksum)) // mismatch hash
+	are.True(errors.Is(db.Verify(ctx, pkgName, "v1.3.0", zipHash), errup.ErrChecksum))     // unknown version
+}
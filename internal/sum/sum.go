@@ -0,0 +1,161 @@
+// Copyright (c) 2020 Hervé Gouchet. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+// Package sum provides methods to maintain the go.sum file of a module
+// and to verify the module versions against a checksum database.
+package sum
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/rvflash/goup/internal/errors"
+
+	"golang.org/x/mod/module"
+	"golang.org/x/mod/sumdb/dirhash"
+)
+
+// Filename is the name of the file listing the hashes of the module versions.
+const Filename = "go.sum"
+
+const (
+	modFilename = "go.mod"
+	// ModSuffix is added to the version of the hash of a go.mod file.
+	ModSuffix = "/" + modFilename
+	numFields = 3
+)
+
+// HashZip returns the hash of the files of this zip archive of a module version, as written in the go.sum file.
+func HashZip(data []byte) (string, error) {
+	z, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return "", fmt.Errorf("%w: %s", errors.ErrChecksum, err.Error())
+	}
+	var (
+		names = make([]string, 0, len(z.File))
+		files = make(map[string]*zip.File, len(z.File))
+	)
+	for _, f := range z.File {
+		names = append(names, f.Name)
+		files[f.Name] = f
+	}
+	return dirhash.Hash1(names, func(name string) (io.ReadCloser, error) {
+		f, ok := files[name]
+		if !ok {
+			return nil, errors.NewMissingData(name)
+		}
+		return f.Open()
+	})
+}
+
+// HashMod returns the hash of this content of a go.mod file, as written in the go.sum file.
+func HashMod(data []byte) (string, error) {
+	return dirhash.Hash1([]string{modFilename}, func(string) (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(data)), nil
+	})
+}
+
+// File is a go.sum file.
+// The hash of the zip archive of a module version is listed under its version,
+// the one of its go.mod file under its version suffixed by /go.mod.
+type File struct {
+	hashes map[module.Version][]string
+}
+
+// Parse parses the content of a go.sum file.
+func Parse(data []byte) (*File, error) {
+	f := &File{hashes: make(map[module.Version][]string)}
+	buf := bufio.NewScanner(bytes.NewReader(data))
+	for n := 1; buf.Scan(); n++ {
+		line := strings.TrimSpace(buf.Text())
+		if line == "" {
+			continue
+		}
+		p := strings.Fields(line)
+		if len(p) != numFields {
+			return nil, fmt.Errorf("%w: line %d: %s", errors.ErrSum, n, line)
+		}
+		v := module.Version{Path: p[0], Version: p[1]}
+		f.hashes[v] = append(f.hashes[v], p[2])
+	}
+	if err := buf.Err(); err != nil {
+		return nil, fmt.Errorf("%w: %s", errors.ErrSum, err.Error())
+	}
+	return f, nil
+}
+
+// Read reads the go.sum file behind this path. A missing file is empty.
+func Read(name string) (*File, error) {
+	b, err := os.ReadFile(name)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("%w: %s", errors.ErrSum, err.Error())
+	}
+	return Parse(b)
+}
+
+// Hash returns the hashes known for this module version.
+// The version of a go.mod file is suffixed by /go.mod.
+func (f *File) Hash(path, version string) []string {
+	if f == nil {
+		return nil
+	}
+	return f.hashes[module.Version{Path: path, Version: version}]
+}
+
+// Add adds the hash of this module version, or of its go.mod file with a version suffixed by /go.mod.
+// It fails if another hash of the same kind is already known, as the content of a published version never changes.
+func (f *File) Add(path, version, hash string) error {
+	if f == nil || path == "" || version == "" || hash == "" {
+		return errors.ErrMissing
+	}
+	v := module.Version{Path: path, Version: version}
+	// The kind of hash is its prefix, like h1.
+	kind, _, _ := strings.Cut(hash, ":")
+	for _, h := range f.hashes[v] {
+		if h == hash {
+			return nil
+		}
+		if strings.HasPrefix(h, kind+":") {
+			return fmt.Errorf("%w: %s %s: %s, expected %s", errors.ErrChecksum, path, version, hash, h)
+		}
+	}
+	f.hashes[v] = append(f.hashes[v], hash)
+	return nil
+}
+
+// Remove removes the hashes of this module version.
+// The version of a go.mod file is suffixed by /go.mod.
+func (f *File) Remove(path, version string) {
+	if f == nil {
+		return
+	}
+	delete(f.hashes, module.Version{Path: path, Version: version})
+}
+
+// Format returns the content of the go.sum file, sorted as the go command does.
+func (f *File) Format() []byte {
+	if f == nil {
+		return nil
+	}
+	list := make([]module.Version, 0, len(f.hashes))
+	for v := range f.hashes {
+		list = append(list, v)
+	}
+	module.Sort(list)
+	var buf bytes.Buffer
+	for _, v := range list {
+		hashes := append([]string(nil), f.hashes[v]...)
+		sort.Strings(hashes)
+		for _, h := range hashes {
+			buf.WriteString(v.Path + " " + v.Version + " " + h + "\n")
+		}
+	}
+	return buf.Bytes()
+}
@@ -0,0 +1,112 @@
+// Copyright (c) 2020 Hervé Gouchet. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package sum_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/matryer/is"
+	errup "github.com/rvflash/goup/internal/errors"
+	"github.com/rvflash/goup/internal/sum"
+
+	"golang.org/x/mod/sumdb/dirhash"
+)
+
+const (
+	pkgName = "example.com/pkg"
+	content = `example.com/pkg v1.1.0 h1:zip110=
+example.com/pkg v1.1.0/go.mod h1:mod110=
+example.com/pkg v1.10.0/go.mod h1:mod1100=
+example.com/pkg v1.2.0/go.mod h1:mod120=
+example.com/a v0.1.0/go.mod h1:moda=
+`
+	// As the go command does, the versions are sorted by semantic versioning.
+	sorted = `example.com/a v0.1.0/go.mod h1:moda=
+example.com/pkg v1.1.0 h1:zip110=
+example.com/pkg v1.1.0/go.mod h1:mod110=
+example.com/pkg v1.2.0/go.mod h1:mod120=
+example.com/pkg v1.10.0/go.mod h1:mod1100=
+`
+)
+
+func TestHashZip(t *testing.T) {
+	t.Parallel()
+	are := is.New(t)
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for name, data := range map[string]string{
+		pkgName + "@v1.2.0/go.mod":  "module " + pkgName + "\n",
+		pkgName + "@v1.2.0/main.go": "package pkg\n",
+	} {
+		f, err := w.Create(name)
+		are.NoErr(err) // unexpected error
+		_, err = f.Write([]byte(data))
+		are.NoErr(err) // unexpected error
+	}
+	are.NoErr(w.Close()) // unexpected error
+	name := filepath.Join(t.TempDir(), "v1.2.0.zip")
+	are.NoErr(os.WriteFile(name, buf.Bytes(), 0o600)) // unexpected error
+
+	exp, err := dirhash.HashZip(name, dirhash.Hash1)
+	are.NoErr(err) // unexpected error
+	res, err := sum.HashZip(buf.Bytes())
+	are.NoErr(err)      // unexpected error
+	are.Equal(res, exp) // mismatch hash
+	_, err = sum.HashZip([]byte("zip"))
+	are.True(errors.Is(err, errup.ErrChecksum)) // mismatch error
+}
+
+func TestHashMod(t *testing.T) {
+	t.Parallel()
+	are := is.New(t)
+	// The hash of the go.mod file of github.com/matryer/is v1.4.1.
+	res, err := sum.HashMod([]byte("module github.com/matryer/is\n\ngo 1.14\n"))
+	are.NoErr(err)                                                    // unexpected error
+	are.Equal(res, "h1:8I/i5uYgLzgsgEloJE1U6xx5HkBQpAZvepWuujKwMRU=") // mismatch hash
+}
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+	are := is.New(t)
+	_, err := sum.Parse([]byte("example.com/pkg v1.0.0\n"))
+	are.True(errors.Is(err, errup.ErrSum)) // mismatch error
+
+	f, err := sum.Parse([]byte(content))
+	are.NoErr(err)                                               // unexpected error
+	are.Equal(f.Hash(pkgName, "v1.1.0"), []string{"h1:zip110="}) // mismatch zip hash
+	are.Equal(f.Hash(pkgName, "v1.2.0"), nil)                    // unexpected zip hash
+	are.Equal(string(f.Format()), sorted)                        // mismatch content
+}
+
+func TestRead(t *testing.T) {
+	t.Parallel()
+	are := is.New(t)
+	f, err := sum.Read(filepath.Join(t.TempDir(), sum.Filename))
+	are.NoErr(err)                         // a missing file is empty
+	are.Equal(len(f.Format()), 0)          // mismatch content
+	_, err = sum.Read(t.TempDir())         // directory
+	are.True(errors.Is(err, errup.ErrSum)) // mismatch error
+}
+
+func TestFile_Add(t *testing.T) {
+	t.Parallel()
+	are := is.New(t)
+	f, err := sum.Parse([]byte(content))
+	are.NoErr(err) // unexpected error
+
+	are.True(errors.Is(f.Add(pkgName, "v1.2.0", ""), errup.ErrMissing))                         // mismatch error
+	are.NoErr(f.Add(pkgName, "v1.1.0", "h1:zip110="))                                           // known hash
+	are.True(errors.Is(f.Add(pkgName, "v1.1.0"+sum.ModSuffix, "h1:other="), errup.ErrChecksum)) // mismatch error
+	are.NoErr(f.Add(pkgName, "v1.2.0", "h1:zip120="))                                           // unexpected error
+	are.Equal(f.Hash(pkgName, "v1.2.0"), []string{"h1:zip120="})                                // mismatch hash
+	f.Remove(pkgName, "v1.1.0")
+	are.Equal(f.Hash(pkgName, "v1.1.0"), nil)                                  // unexpected hash
+	are.Equal(f.Hash(pkgName, "v1.1.0"+sum.ModSuffix), []string{"h1:mod110="}) // mismatch go.mod hash
+}
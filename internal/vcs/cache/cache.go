@@ -0,0 +1,115 @@
+// Copyright (c) 2020 Hervé Gouchet. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+// Package cache provides a persistent cache of the remote properties, like the list of tags.
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/rvflash/goup/internal/errors"
+)
+
+// Name is the name of the cache directory.
+const Name = "goup"
+
+const (
+	ext  = ".json"
+	perm = 0o700
+)
+
+// Open returns a new store of data in the given directory.
+// If the directory is empty, a goup directory is used in the user's cache directory.
+// The data expire after the given time duration.
+func Open(dir string, ttl time.Duration) (*Store, error) {
+	if ttl <= 0 {
+		return nil, errors.NewMissingData("cache ttl")
+	}
+	if dir == "" {
+		root, err := os.UserCacheDir()
+		if err != nil {
+			return nil, err
+		}
+		dir = filepath.Join(root, Name)
+	}
+	err := os.MkdirAll(dir, perm)
+	if err != nil {
+		return nil, err
+	}
+	return &Store{dir: dir, ttl: ttl, now: time.Now}, nil
+}
+
+// Store is a file store: each data is stored in its own JSON file.
+type Store struct {
+	dir string
+	ttl time.Duration
+	now func() time.Time
+}
+
+type entry struct {
+	Key     string          `json:"key"`
+	Expires time.Time       `json:"expires"`
+	Data    json.RawMessage `json:"data"`
+}
+
+// Get implements the vcs.Cache interface.
+// It returns false if the key is unknown or expired.
+func (s *Store) Get(key string, v interface{}) bool {
+	if s == nil {
+		return false
+	}
+	b, err := os.ReadFile(s.filename(key))
+	if err != nil {
+		return false
+	}
+	var e entry
+	if err = json.Unmarshal(b, &e); err != nil {
+		return false
+	}
+	if e.Key != key || !s.now().Before(e.Expires) {
+		return false
+	}
+	return json.Unmarshal(e.Data, v) == nil
+}
+
+// Set implements the vcs.Cache interface.
+func (s *Store) Set(key string, v interface{}) error {
+	if s == nil {
+		return errors.NewMissingData("cache")
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	b, err := json.Marshal(entry{Key: key, Expires: s.now().Add(s.ttl), Data: data})
+	if err != nil {
+		return err
+	}
+	// Writes in a temporary file before renaming it to avoid partial reads by concurrent runs.
+	f, err := os.CreateTemp(s.dir, "*"+ext)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(b)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(f.Name(), s.filename(key))
+	}
+	if err != nil {
+		_ = os.Remove(f.Name())
+	}
+	return err
+}
+
+func (s *Store) filename(key string) string {
+	h := sha256.Sum256([]byte(key))
+	return filepath.Join(s.dir, hex.EncodeToString(h[:])+ext)
+}
@@ -0,0 +1,77 @@
+// Copyright (c) 2020 Hervé Gouchet. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package cache_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/matryer/is"
+	errup "github.com/rvflash/goup/internal/errors"
+	"github.com/rvflash/goup/internal/vcs/cache"
+)
+
+const key = "git https://github.com/rvflash/goup"
+
+func TestOpen(t *testing.T) {
+	t.Parallel()
+	are := is.New(t)
+
+	t.Run("no ttl", func(t *testing.T) {
+		t.Parallel()
+		s, err := cache.Open(t.TempDir(), 0)
+		are.True(errors.Is(err, errup.ErrMissing)) // mismatch error
+		are.True(s == nil)                         // mismatch result
+	})
+
+	t.Run("ok", func(t *testing.T) {
+		t.Parallel()
+		s, err := cache.Open(t.TempDir(), time.Hour)
+		are.NoErr(err)     // unexpected error
+		are.True(s != nil) // mismatch result
+	})
+}
+
+func TestStore_Get(t *testing.T) {
+	t.Parallel()
+	var (
+		are = is.New(t)
+		dt  = map[string]struct {
+			ttl time.Duration
+			set bool
+			out []string
+		}{
+			"unknown": {ttl: time.Hour},
+			"expired": {ttl: time.Nanosecond, set: true},
+			"ok":      {ttl: time.Hour, set: true, out: []string{"v1.0.0", "v1.1.0"}},
+		}
+	)
+	for name, ts := range dt {
+		tt := ts
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			s, err := cache.Open(t.TempDir(), tt.ttl)
+			are.NoErr(err) // unexpected error
+			if tt.set {
+				are.NoErr(s.Set(key, []string{"v1.0.0", "v1.1.0"})) // unexpected error
+				time.Sleep(time.Millisecond)
+			}
+			var out []string
+			are.Equal(s.Get(key, &out), tt.out != nil) // mismatch hit
+			are.Equal(out, tt.out)                     // mismatch result
+		})
+	}
+}
+
+func TestStore_Set(t *testing.T) {
+	t.Parallel()
+	var (
+		are = is.New(t)
+		s   *cache.Store
+	)
+	are.True(errors.Is(s.Set(key, nil), errup.ErrMissing)) // mismatch error
+	are.True(!s.Get(key, nil))                             // mismatch hit
+}
@@ -0,0 +1,90 @@
+// Copyright (c) 2020 Hervé Gouchet. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package cache
+
+import (
+	"encoding/json"
+	"sync"
+
+	"github.com/rvflash/goup/internal/errors"
+	"github.com/rvflash/goup/internal/vcs"
+)
+
+// NewMemory returns a new in-memory store of data.
+// Without expiration, it is used to share the remote properties during a run.
+func NewMemory() *Memory {
+	return &Memory{data: make(map[string][]byte)}
+}
+
+// Memory is an in-memory store, safe for concurrent use.
+type Memory struct {
+	mu   sync.RWMutex
+	data map[string][]byte
+}
+
+// Get implements the vcs.Cache interface.
+func (m *Memory) Get(key string, v interface{}) bool {
+	if m == nil {
+		return false
+	}
+	m.mu.RLock()
+	b, ok := m.data[key]
+	m.mu.RUnlock()
+	return ok && json.Unmarshal(b, v) == nil
+}
+
+// Set implements the vcs.Cache interface.
+func (m *Memory) Set(key string, v interface{}) error {
+	if m == nil {
+		return errors.NewMissingData("cache")
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	m.mu.Lock()
+	m.data[key] = b
+	m.mu.Unlock()
+	return nil
+}
+
+// Chain returns a cache looking for the data in each of these caches, in order.
+// The data found in a cache are copied in the previous ones and the new data are stored in all of them.
+// The nil caches are ignored.
+func Chain(caches ...vcs.Cache) vcs.Cache {
+	var c chain
+	for _, s := range caches {
+		if s != nil {
+			c = append(c, s)
+		}
+	}
+	return c
+}
+
+type chain []vcs.Cache
+
+// Get implements the vcs.Cache interface.
+func (c chain) Get(key string, v interface{}) bool {
+	for k, s := range c {
+		if s.Get(key, v) {
+			for _, p := range c[:k] {
+				_ = p.Set(key, v)
+			}
+			return true
+		}
+	}
+	return false
+}
+
+// Set implements the vcs.Cache interface.
+func (c chain) Set(key string, v interface{}) error {
+	var err error
+	for _, s := range c {
+		if e := s.Set(key, v); e != nil && err == nil {
+			err = e
+		}
+	}
+	return err
+}
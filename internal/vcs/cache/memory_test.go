@@ -0,0 +1,50 @@
+// Copyright (c) 2020 Hervé Gouchet. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package cache_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/matryer/is"
+	errup "github.com/rvflash/goup/internal/errors"
+	"github.com/rvflash/goup/internal/vcs/cache"
+)
+
+func TestMemory(t *testing.T) {
+	t.Parallel()
+	var (
+		are = is.New(t)
+		m   *cache.Memory
+		out []string
+	)
+	are.True(!m.Get(key, &out))                                           // mismatch default
+	are.True(errors.Is(m.Set(key, []string{"v1.0.0"}), errup.ErrMissing)) // mismatch default error
+	m = cache.NewMemory()
+	are.True(!m.Get(key, &out))               // mismatch unknown
+	are.NoErr(m.Set(key, []string{"v1.0.0"})) // unexpected error
+	are.True(m.Get(key, &out))                // mismatch known
+	are.Equal(out, []string{"v1.0.0"})        // mismatch data
+}
+
+func TestChain(t *testing.T) {
+	t.Parallel()
+	are := is.New(t)
+	s, err := cache.Open(t.TempDir(), time.Hour)
+	are.NoErr(err)                            // unexpected error
+	are.NoErr(s.Set(key, []string{"v1.0.0"})) // unexpected error
+	var (
+		m   = cache.NewMemory()
+		c   = cache.Chain(m, nil, s)
+		out []string
+	)
+	are.True(c.Get(key, &out))                    // mismatch stored
+	are.Equal(out, []string{"v1.0.0"})            // mismatch data
+	are.True(m.Get(key, &out))                    // expected copy in memory
+	are.NoErr(c.Set("other", []string{"v1.1.0"})) // unexpected error
+	are.True(s.Get("other", &out))                // expected data in store
+	are.True(!cache.Chain().Get(key, &out))       // mismatch empty chain
+}
@@ -0,0 +1,158 @@
+// Copyright (c) 2020 Hervé Gouchet. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package cache
+
+import (
+	"context"
+	"strings"
+
+	"github.com/rvflash/goup/internal/errors"
+	"github.com/rvflash/goup/internal/semver"
+	"github.com/rvflash/goup/internal/vcs"
+)
+
+// VCS decorates a version control system to cache the list of tags.
+// Only the successful responses are cached.
+type VCS struct {
+	vcs.System
+	name   string
+	prefix string
+	cache  vcs.Cache
+}
+
+// Option allows to customize the VCS.
+type Option func(s *VCS)
+
+// WithNamespace adds this namespace to the prefix of the cache keys,
+// like the settings of the decorated system changing its responses.
+func WithNamespace(namespace string) Option {
+	return func(s *VCS) {
+		if namespace != "" {
+			s.prefix = Key(s.name, namespace)
+		}
+	}
+}
+
+// New returns a new instance of VCS.
+// The name of the decorated system is used to prefix the cache keys.
+func New(name string, system vcs.System, cache vcs.Cache, opts ...Option) *VCS {
+	s := &VCS{
+		System: system,
+		name:   name,
+		prefix: name,
+		cache:  cache,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// FetchPath implements the vcs.VCS interface.
+func (s *VCS) FetchPath(ctx context.Context, path string) (semver.Tags, error) {
+	return s.fetch(Key(s.prefix, "path", path), func() (semver.Tags, error) {
+		return s.System.FetchPath(ctx, path)
+	})
+}
+
+// FetchURL implements the vcs.VCS interface.
+func (s *VCS) FetchURL(ctx context.Context, url string) (semver.Tags, error) {
+	return s.fetch(Key(s.prefix, "url", url), func() (semver.Tags, error) {
+		return s.System.FetchURL(ctx, url)
+	})
+}
+
+// FetchMod implements the vcs.ModFetcher interface.
+// It fails with errors.ErrSystem if the decorated system can not read go.mod files.
+func (s *VCS) FetchMod(ctx context.Context, path, version string) ([]byte, error) {
+	system, ok := s.System.(vcs.ModFetcher)
+	if !ok {
+		return nil, vcs.Errorf(s.name, errors.ErrSystem)
+	}
+	return s.fetchMod(Key(s.prefix, "mod", path, version), func() ([]byte, error) {
+		return system.FetchMod(ctx, path, version)
+	})
+}
+
+// FetchModURL implements the vcs.ModFetcher interface.
+func (s *VCS) FetchModURL(ctx context.Context, url, dir, version string) ([]byte, error) {
+	system, ok := s.System.(vcs.ModFetcher)
+	if !ok {
+		return nil, vcs.Errorf(s.name, errors.ErrSystem)
+	}
+	return s.fetchMod(Key(s.prefix, "mod", url, dir, version), func() ([]byte, error) {
+		return system.FetchModURL(ctx, url, dir, version)
+	})
+}
+
+// FetchHead implements the vcs.HeadFetcher interface.
+// The default branch moves, so its last commit is never cached.
+func (s *VCS) FetchHead(ctx context.Context, path string) (*vcs.Revision, error) {
+	system, ok := s.System.(vcs.HeadFetcher)
+	if !ok {
+		return nil, vcs.Errorf(s.name, errors.ErrSystem)
+	}
+	return system.FetchHead(ctx, path)
+}
+
+// FetchHeadURL implements the vcs.HeadFetcher interface.
+func (s *VCS) FetchHeadURL(ctx context.Context, url string) (*vcs.Revision, error) {
+	system, ok := s.System.(vcs.HeadFetcher)
+	if !ok {
+		return nil, vcs.Errorf(s.name, errors.ErrSystem)
+	}
+	return system.FetchHeadURL(ctx, url)
+}
+
+// FetchZip implements the vcs.ZipFetcher interface.
+// The archives are only downloaded to compute their hash, so they are never cached.
+func (s *VCS) FetchZip(ctx context.Context, path, version string) ([]byte, error) {
+	system, ok := s.System.(vcs.ZipFetcher)
+	if !ok {
+		return nil, vcs.Errorf(s.name, errors.ErrSystem)
+	}
+	return system.FetchZip(ctx, path, version)
+}
+
+func (s *VCS) fetchMod(key string, fn func() ([]byte, error)) ([]byte, error) {
+	var content string
+	if s.cache != nil && s.cache.Get(key, &content) {
+		return []byte(content), nil
+	}
+	res, err := fn()
+	if err != nil || s.cache == nil {
+		return res, err
+	}
+	// A failure to write in the cache must not fail the check.
+	_ = s.cache.Set(key, string(res))
+	return res, nil
+}
+
+func (s *VCS) fetch(key string, fn func() (semver.Tags, error)) (semver.Tags, error) {
+	var versions []string
+	if s.cache != nil && s.cache.Get(key, &versions) {
+		tags := make(semver.Tags, len(versions))
+		for k, v := range versions {
+			tags[k] = semver.New(v)
+		}
+		return tags, nil
+	}
+	tags, err := fn()
+	if err != nil || s.cache == nil {
+		return tags, err
+	}
+	versions = make([]string, len(tags))
+	for k, v := range tags {
+		versions[k] = v.String()
+	}
+	// A failure to write in the cache must not fail the check.
+	_ = s.cache.Set(key, versions)
+	return tags, nil
+}
+
+// Key returns the cache key for these parts.
+func Key(parts ...string) string {
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,171 @@
+// Copyright (c) 2020 Hervé Gouchet. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package cache_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/matryer/is"
+	errup "github.com/rvflash/goup/internal/errors"
+	"github.com/rvflash/goup/internal/semver"
+	"github.com/rvflash/goup/internal/vcs"
+	"github.com/rvflash/goup/internal/vcs/cache"
+	mockvcs "github.com/rvflash/goup/testdata/mock/vcs"
+
+	"go.uber.org/mock/gomock"
+)
+
+const (
+	name    = "git"
+	pkgName = "github.com/rvflash/goup"
+	repoURL = "https://" + pkgName
+)
+
+func TestVCS_FetchPath(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	are := is.New(t)
+	store, err := cache.Open(t.TempDir(), time.Hour)
+	are.NoErr(err) // unexpected error
+
+	tags := semver.Tags{semver.New("v0.1.0"), semver.New("refs/tags/v0.2.0")}
+	m := mockvcs.NewMockSystem(ctrl)
+	m.EXPECT().FetchPath(gomock.Any(), pkgName).Return(tags, nil).Times(1)
+	s := cache.New(name, m, store)
+	for i := 0; i < 2; i++ {
+		res, err := s.FetchPath(context.Background(), pkgName)
+		are.NoErr(err)                               // unexpected error
+		are.Equal(len(res), 2)                       // mismatch length
+		are.Equal(res[1].String(), tags[1].String()) // mismatch raw version
+		are.Equal(res[1].Canonical(), "v0.2.0")      // mismatch version
+	}
+}
+
+func TestWithNamespace(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	are := is.New(t)
+	store, err := cache.Open(t.TempDir(), time.Hour)
+	are.NoErr(err) // unexpected error
+
+	// The same path listed by two proxies is cached apart.
+	tags := semver.Tags{semver.New("v0.1.0")}
+	m := mockvcs.NewMockSystem(ctrl)
+	m.EXPECT().FetchPath(gomock.Any(), pkgName).Return(tags, nil).Times(2)
+	for _, ns := range []string{"https://a.example.com", "https://b.example.com", "https://a.example.com"} {
+		res, err := cache.New(name, m, store, cache.WithNamespace(ns)).FetchPath(context.Background(), pkgName)
+		are.NoErr(err)         // unexpected error
+		are.Equal(len(res), 1) // mismatch length
+	}
+}
+
+func TestVCS_FetchURL(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	are := is.New(t)
+	store, err := cache.Open(t.TempDir(), time.Hour)
+	are.NoErr(err) // unexpected error
+
+	// Failures are not cached.
+	m := mockvcs.NewMockSystem(ctrl)
+	m.EXPECT().FetchURL(gomock.Any(), repoURL).Return(nil, errup.ErrFetch).Times(2)
+	s := cache.New(name, m, store)
+	for i := 0; i < 2; i++ {
+		_, err = s.FetchURL(context.Background(), repoURL)
+		are.Equal(err, errup.ErrFetch) // mismatch error
+	}
+}
+
+func TestVCS_FetchMod(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	are := is.New(t)
+	store, err := cache.Open(t.TempDir(), time.Hour)
+	are.NoErr(err) // unexpected error
+
+	const content = "module " + pkgName + "\n"
+	m := struct {
+		*mockvcs.MockSystem
+		*mockvcs.MockModFetcher
+	}{
+		MockSystem:     mockvcs.NewMockSystem(ctrl),
+		MockModFetcher: mockvcs.NewMockModFetcher(ctrl),
+	}
+	m.MockModFetcher.EXPECT().FetchMod(gomock.Any(), pkgName, "v0.1.0").Return([]byte(content), nil).Times(1)
+	s := cache.New(name, m, store)
+	for i := 0; i < 2; i++ {
+		res, err := s.FetchMod(context.Background(), pkgName, "v0.1.0")
+		are.NoErr(err)                  // unexpected error
+		are.Equal(string(res), content) // mismatch content
+	}
+	// Without the capacity to read go.mod files.
+	s = cache.New(name, mockvcs.NewMockSystem(ctrl), store)
+	_, err = s.FetchModURL(context.Background(), repoURL, "", "v0.1.0")
+	are.True(errors.Is(err, errup.ErrSystem)) // mismatch error
+}
+
+func TestVCS_FetchHead(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	are := is.New(t)
+	store, err := cache.Open(t.TempDir(), time.Hour)
+	are.NoErr(err) // unexpected error
+
+	rev := &vcs.Revision{Hash: "accd165b1659", Time: time.Date(2020, 1, 21, 19, 2, 30, 0, time.UTC)}
+	m := struct {
+		*mockvcs.MockSystem
+		*mockvcs.MockHeadFetcher
+	}{
+		MockSystem:      mockvcs.NewMockSystem(ctrl),
+		MockHeadFetcher: mockvcs.NewMockHeadFetcher(ctrl),
+	}
+	// The default branch moves, so its head is never cached.
+	m.MockHeadFetcher.EXPECT().FetchHead(gomock.Any(), pkgName).Return(rev, nil).Times(2)
+	s := cache.New(name, m, store)
+	for i := 0; i < 2; i++ {
+		res, err := s.FetchHead(context.Background(), pkgName)
+		are.NoErr(err)      // unexpected error
+		are.Equal(res, rev) // mismatch revision
+	}
+	// Without the capacity to read the default branch.
+	s = cache.New(name, mockvcs.NewMockSystem(ctrl), store)
+	_, err = s.FetchHeadURL(context.Background(), repoURL)
+	are.True(errors.Is(err, errup.ErrSystem)) // mismatch error
+}
+
+func TestVCS_FetchZip(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	are := is.New(t)
+	store, err := cache.Open(t.TempDir(), time.Hour)
+	are.NoErr(err) // unexpected error
+
+	m := struct {
+		*mockvcs.MockSystem
+		*mockvcs.MockZipFetcher
+	}{
+		MockSystem:     mockvcs.NewMockSystem(ctrl),
+		MockZipFetcher: mockvcs.NewMockZipFetcher(ctrl),
+	}
+	// The archives are never cached.
+	m.MockZipFetcher.EXPECT().FetchZip(gomock.Any(), pkgName, "v0.1.0").Return([]byte("zip"), nil).Times(2)
+	s := cache.New(name, m, store)
+	for i := 0; i < 2; i++ {
+		res, err := s.FetchZip(context.Background(), pkgName, "v0.1.0")
+		are.NoErr(err)                // unexpected error
+		are.Equal(string(res), "zip") // mismatch content
+	}
+	_, err = cache.New(name, mockvcs.NewMockSystem(ctrl), store).FetchZip(context.Background(), pkgName, "v0.1.0")
+	are.True(errors.Is(err, errup.ErrSystem)) // mismatch error
+}
@@ -0,0 +1,182 @@
+// Copyright (c) 2020 Hervé Gouchet. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+// Package flight provides a decorator sharing the tags fetched by a version control system during a run.
+// The concurrent requests of the same path wait for the one in flight and the later ones reuse its result.
+package flight
+
+import (
+	"context"
+	"sync"
+
+	"github.com/rvflash/goup/internal/errors"
+	"github.com/rvflash/goup/internal/semver"
+	"github.com/rvflash/goup/internal/vcs"
+	"github.com/rvflash/goup/internal/vcs/cache"
+)
+
+// NewGroup returns a new group of requests, to share between the systems of a run.
+func NewGroup() *Group {
+	return &Group{calls: make(map[string]*call)}
+}
+
+// Group memoizes the requests in flight and those completed.
+// Only the successful responses are kept, a failure is only shared with the requests waiting for it.
+type Group struct {
+	mu    sync.Mutex
+	calls map[string]*call
+}
+
+type call struct {
+	done chan struct{}
+	tags semver.Tags
+	err  error
+	// stopped is true if the call has failed once the context of its caller done.
+	stopped bool
+}
+
+// do returns the result of the call behind this key, only executing fn if there is no such call.
+// fn runs with the context of the request starting the call, the others wait for it with their own context.
+// If the call fails as its context is done, like at the timeout of its dependency,
+// the requests waiting for it try again, with their own context. They do the same if fn panics.
+func (g *Group) do(ctx context.Context, key string, fn func() (semver.Tags, error)) (semver.Tags, error) {
+	g.mu.Lock()
+	if c, ok := g.calls[key]; ok {
+		g.mu.Unlock()
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-c.done:
+			if c.stopped {
+				return g.do(ctx, key, fn)
+			}
+			return c.result()
+		}
+	}
+	c := &call{done: make(chan struct{})}
+	g.calls[key] = c
+	g.mu.Unlock()
+
+	var returned bool
+	defer func() {
+		if !returned {
+			// fn panicked: the requests waiting for it try again and the panic goes on.
+			c.stopped = true
+		}
+		if c.err != nil || c.stopped {
+			g.mu.Lock()
+			delete(g.calls, key)
+			g.mu.Unlock()
+		}
+		close(c.done)
+	}()
+	c.tags, c.err = fn()
+	c.stopped = c.err != nil && ctx.Err() != nil
+	returned = true
+	return c.result()
+}
+
+// result returns a copy of the tags, as the callers can sort them.
+func (c *call) result() (semver.Tags, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	return append(semver.Tags(nil), c.tags...), nil
+}
+
+// VCS decorates a version control system to share its tags with the group.
+type VCS struct {
+	vcs.System
+	name   string
+	prefix string
+	group  *Group
+}
+
+// Option allows to customize the VCS.
+type Option func(s *VCS)
+
+// WithNamespace adds this namespace to the prefix of the keys of the requests,
+// like the settings of the decorated system changing its responses.
+func WithNamespace(namespace string) Option {
+	return func(s *VCS) {
+		if namespace != "" {
+			s.prefix = cache.Key(s.name, namespace)
+		}
+	}
+}
+
+// New returns a new instance of VCS.
+// The name of the decorated system is used to prefix the keys of its requests.
+func New(name string, system vcs.System, group *Group, opts ...Option) *VCS {
+	s := &VCS{
+		System: system,
+		name:   name,
+		prefix: name,
+		group:  group,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// FetchPath implements the vcs.VCS interface.
+func (s *VCS) FetchPath(ctx context.Context, path string) (semver.Tags, error) {
+	return s.group.do(ctx, cache.Key(s.prefix, "path", path), func() (semver.Tags, error) {
+		return s.System.FetchPath(ctx, path)
+	})
+}
+
+// FetchURL implements the vcs.VCS interface.
+func (s *VCS) FetchURL(ctx context.Context, url string) (semver.Tags, error) {
+	return s.group.do(ctx, cache.Key(s.prefix, "url", url), func() (semver.Tags, error) {
+		return s.System.FetchURL(ctx, url)
+	})
+}
+
+// FetchMod implements the vcs.ModFetcher interface.
+// It fails with errors.ErrSystem if the decorated system can not read go.mod files.
+func (s *VCS) FetchMod(ctx context.Context, path, version string) ([]byte, error) {
+	system, ok := s.System.(vcs.ModFetcher)
+	if !ok {
+		return nil, vcs.Errorf(s.name, errors.ErrSystem)
+	}
+	return system.FetchMod(ctx, path, version)
+}
+
+// FetchModURL implements the vcs.ModFetcher interface.
+func (s *VCS) FetchModURL(ctx context.Context, url, dir, version string) ([]byte, error) {
+	system, ok := s.System.(vcs.ModFetcher)
+	if !ok {
+		return nil, vcs.Errorf(s.name, errors.ErrSystem)
+	}
+	return system.FetchModURL(ctx, url, dir, version)
+}
+
+// FetchHead implements the vcs.HeadFetcher interface.
+func (s *VCS) FetchHead(ctx context.Context, path string) (*vcs.Revision, error) {
+	system, ok := s.System.(vcs.HeadFetcher)
+	if !ok {
+		return nil, vcs.Errorf(s.name, errors.ErrSystem)
+	}
+	return system.FetchHead(ctx, path)
+}
+
+// FetchHeadURL implements the vcs.HeadFetcher interface.
+func (s *VCS) FetchHeadURL(ctx context.Context, url string) (*vcs.Revision, error) {
+	system, ok := s.System.(vcs.HeadFetcher)
+	if !ok {
+		return nil, vcs.Errorf(s.name, errors.ErrSystem)
+	}
+	return system.FetchHeadURL(ctx, url)
+}
+
+// FetchZip implements the vcs.ZipFetcher interface.
+func (s *VCS) FetchZip(ctx context.Context, path, version string) ([]byte, error) {
+	system, ok := s.System.(vcs.ZipFetcher)
+	if !ok {
+		return nil, vcs.Errorf(s.name, errors.ErrSystem)
+	}
+	return system.FetchZip(ctx, path, version)
+}
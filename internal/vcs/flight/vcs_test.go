@@ -0,0 +1,217 @@
+// Copyright (c) 2020 Hervé Gouchet. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package flight_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/matryer/is"
+	errup "github.com/rvflash/goup/internal/errors"
+	"github.com/rvflash/goup/internal/semver"
+	"github.com/rvflash/goup/internal/vcs/flight"
+	mockvcs "github.com/rvflash/goup/testdata/mock/vcs"
+
+	"go.uber.org/mock/gomock"
+)
+
+const (
+	name    = "git"
+	pkgName = "github.com/rvflash/goup"
+	repoURL = "https://" + pkgName
+	calls   = 10
+)
+
+func TestVCS_FetchPath(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	var (
+		are  = is.New(t)
+		tags = semver.Tags{semver.New("v0.2.0"), semver.New("v0.1.0")}
+		m    = mockvcs.NewMockSystem(ctrl)
+		wg   sync.WaitGroup
+	)
+	m.EXPECT().FetchPath(gomock.Any(), pkgName).DoAndReturn(func(context.Context, string) (semver.Tags, error) {
+		// Keeps the request in flight while the others arrive.
+		time.Sleep(10 * time.Millisecond)
+		return tags, nil
+	}).Times(1)
+	s := flight.New(name, m, flight.NewGroup())
+	for i := 0; i < calls; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			res, err := s.FetchPath(context.Background(), pkgName)
+			are.NoErr(err)                                   // unexpected error
+			are.Equal(len(res), 2)                           // mismatch length
+			are.Equal(semver.Latest(res).String(), "v0.2.0") // mismatch latest
+		}()
+	}
+	wg.Wait()
+	res, err := s.FetchPath(context.Background(), pkgName)
+	are.NoErr(err)         // unexpected error
+	are.Equal(len(res), 2) // mismatch completed length
+}
+
+func TestVCS_FetchPath_Canceled(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	var (
+		are         = is.New(t)
+		tags        = semver.Tags{semver.New("v0.1.0")}
+		m           = mockvcs.NewMockSystem(ctrl)
+		s           = flight.New(name, m, flight.NewGroup())
+		started     = make(chan struct{})
+		ctx, cancel = context.WithCancel(context.Background())
+		wg          sync.WaitGroup
+	)
+	defer cancel()
+	m.EXPECT().FetchPath(gomock.Any(), pkgName).DoAndReturn(func(ctx context.Context, _ string) (semver.Tags, error) {
+		close(started)
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}).Times(1)
+	m.EXPECT().FetchPath(gomock.Any(), pkgName).Return(tags, nil).Times(1)
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		_, err := s.FetchPath(ctx, pkgName)
+		are.True(errors.Is(err, context.Canceled)) // mismatch error
+	}()
+	<-started
+	go func() {
+		defer wg.Done()
+		// The request waiting for the canceled one tries again with its own context.
+		res, err := s.FetchPath(context.Background(), pkgName)
+		are.NoErr(err)         // unexpected error
+		are.Equal(len(res), 1) // mismatch length
+	}()
+	// Keeps the request in flight while the other one arrives.
+	time.Sleep(10 * time.Millisecond)
+	cancel()
+	wg.Wait()
+}
+
+func TestVCS_FetchPath_Panic(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	var (
+		are     = is.New(t)
+		tags    = semver.Tags{semver.New("v0.1.0")}
+		m       = mockvcs.NewMockSystem(ctrl)
+		s       = flight.New(name, m, flight.NewGroup())
+		started = make(chan struct{})
+		wg      sync.WaitGroup
+	)
+	m.EXPECT().FetchPath(gomock.Any(), pkgName).DoAndReturn(func(context.Context, string) (semver.Tags, error) {
+		close(started)
+		// Keeps the request in flight while the other one arrives.
+		time.Sleep(10 * time.Millisecond)
+		panic("oops")
+	}).Times(1)
+	m.EXPECT().FetchPath(gomock.Any(), pkgName).Return(tags, nil).Times(1)
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		defer func() {
+			are.Equal(recover(), "oops") // the panic must go on
+		}()
+		_, _ = s.FetchPath(context.Background(), pkgName)
+	}()
+	<-started
+	go func() {
+		defer wg.Done()
+		// The request waiting for the panicked one tries again.
+		res, err := s.FetchPath(context.Background(), pkgName)
+		are.NoErr(err)         // unexpected error
+		are.Equal(len(res), 1) // mismatch length
+	}()
+	wg.Wait()
+	// The call is no longer in flight.
+	res, err := s.FetchPath(context.Background(), pkgName)
+	are.NoErr(err)         // unexpected error
+	are.Equal(len(res), 1) // mismatch completed length
+}
+
+func TestWithNamespace(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	var (
+		are   = is.New(t)
+		group = flight.NewGroup()
+		m     = mockvcs.NewMockSystem(ctrl)
+	)
+	// The same path listed by two proxies is requested apart.
+	m.EXPECT().FetchPath(gomock.Any(), pkgName).Return(semver.Tags{semver.New("v0.1.0")}, nil).Times(2)
+	for _, ns := range []string{"https://a.example.com", "https://b.example.com", "https://a.example.com"} {
+		res, err := flight.New(name, m, group, flight.WithNamespace(ns)).FetchPath(context.Background(), pkgName)
+		are.NoErr(err)         // unexpected error
+		are.Equal(len(res), 1) // mismatch length
+	}
+}
+
+func TestVCS_FetchURL(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	var (
+		are = is.New(t)
+		m   = mockvcs.NewMockSystem(ctrl)
+		s   = flight.New(name, m, flight.NewGroup())
+	)
+	// Failures are not kept.
+	m.EXPECT().FetchURL(gomock.Any(), repoURL).Return(nil, errup.ErrFetch).Times(2)
+	for i := 0; i < 2; i++ {
+		_, err := s.FetchURL(context.Background(), repoURL)
+		are.True(errors.Is(err, errup.ErrFetch)) // mismatch error
+	}
+}
+
+func TestVCS_FetchMod(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	var (
+		are = is.New(t)
+		s   = flight.New(name, mockvcs.NewMockSystem(ctrl), flight.NewGroup())
+	)
+	_, err := s.FetchMod(context.Background(), pkgName, "v0.1.0")
+	are.True(errors.Is(err, errup.ErrSystem)) // mismatch error
+	_, err = s.FetchModURL(context.Background(), repoURL, "", "v0.1.0")
+	are.True(errors.Is(err, errup.ErrSystem)) // mismatch error
+}
+
+func TestVCS_FetchHead(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	var (
+		are = is.New(t)
+		s   = flight.New(name, mockvcs.NewMockSystem(ctrl), flight.NewGroup())
+	)
+	_, err := s.FetchHead(context.Background(), pkgName)
+	are.True(errors.Is(err, errup.ErrSystem)) // mismatch error
+	_, err = s.FetchHeadURL(context.Background(), repoURL)
+	are.True(errors.Is(err, errup.ErrSystem)) // mismatch error
+}
+
+func TestVCS_FetchZip(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	var (
+		are = is.New(t)
+		s   = flight.New(name, mockvcs.NewMockSystem(ctrl), flight.NewGroup())
+	)
+	_, err := s.FetchZip(context.Background(), pkgName, "v0.1.0")
+	are.True(errors.Is(err, errup.ErrSystem)) // mismatch error
+}
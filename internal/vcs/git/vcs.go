@@ -7,9 +7,8 @@ package git
 
 import (
 	"context"
+	stderrors "errors"
 	"net/url"
-	"path"
-	"strings"
 
 	"github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/config"
@@ -33,16 +32,51 @@ const (
 type VCS struct {
 	auth    vcs.BasicAuthentifier
 	client  vcs.ClientChooser
+	hosts   vcs.Hosts
+	limiter *vcs.Limiter
+	backoff vcs.Backoff
 	storage storage.Storer
 }
 
+// Option allows to customize the VCS.
+type Option func(s *VCS)
+
+// WithHosts defines the registry used to find the root path of the repositories.
+// By default, only the layouts of the well-known hosts are known.
+func WithHosts(h vcs.Hosts) Option {
+	return func(s *VCS) {
+		s.hosts = h
+	}
+}
+
+// WithLimiter defines the limiter of the requests sent to the remote repositories.
+// By default, they are not limited.
+func WithLimiter(l *vcs.Limiter) Option {
+	return func(s *VCS) {
+		s.limiter = l
+	}
+}
+
+// WithBackoff defines the policy used to retry the listing of the tags failed with a temporary error.
+// By default, there is no retry.
+func WithBackoff(b vcs.Backoff) Option {
+	return func(s *VCS) {
+		s.backoff = b
+	}
+}
+
 // New returns a new instance of VCS.
-func New(client vcs.ClientChooser, auth vcs.BasicAuthentifier) *VCS {
-	return &VCS{
+func New(client vcs.ClientChooser, auth vcs.BasicAuthentifier, opts ...Option) *VCS {
+	s := &VCS{
 		auth:    auth,
 		client:  client,
+		hosts:   vcs.DefaultHosts,
 		storage: memory.NewStorage(),
 	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
 }
 
 // CanFetch implements the vcs.VCS interface.
@@ -62,7 +96,7 @@ func (s *VCS) FetchPath(ctx context.Context, path string) (semver.Tags, error) {
 	}
 	var c = make(chan *reference, oneRef)
 	go func() {
-		c <- s.fetchWithRetry(path)
+		c <- s.fetchPath(ctx, path)
 	}()
 	return tags(ctx, c)
 }
@@ -74,12 +108,101 @@ func (s *VCS) FetchURL(ctx context.Context, url string) (semver.Tags, error) {
 	}
 	var c = make(chan *reference, oneRef)
 	go func() {
-		c <- s.fetch(url)
+		c <- s.fetch(ctx, url)
 	}()
 	return tags(ctx, c)
 }
 
-func (s *VCS) fetchWithRetry(path string) (ref *reference) {
+// FetchHead implements the vcs.HeadFetcher interface.
+// As with gopkg.in, the head of a gopkg.in path is the last commit of the branch named by its major version,
+// like v2 for gopkg.in/yaml.v2, except for v0 served by the default branch.
+func (s *VCS) FetchHead(ctx context.Context, path string) (*vcs.Revision, error) {
+	if !s.ready(ctx) {
+		return nil, errors.ErrSystem
+	}
+	if path == "" {
+		return nil, errors.ErrRepository
+	}
+	var c = make(chan *reference, oneRef)
+	go func() {
+		c <- s.fetchPath(ctx, path)
+	}()
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case ref := <-c:
+		if ref.err != nil {
+			return nil, ref.err
+		}
+		var branch plumbing.ReferenceName
+		if _, major, ok := vcs.Gopkg(path); ok && major != gopkgDefault {
+			branch = plumbing.NewBranchReferenceName(major)
+		}
+		return s.fetchHead(ctx, ref.url, branch)
+	}
+}
+
+// gopkgDefault is the major version of the gopkg.in paths served by the default branch.
+const gopkgDefault = "v0"
+
+// FetchHeadURL implements the vcs.HeadFetcher interface.
+// Only the last commit of the default branch is cloned, in memory.
+func (s *VCS) FetchHeadURL(ctx context.Context, rawURL string) (*vcs.Revision, error) {
+	return s.fetchHead(ctx, rawURL, "")
+}
+
+// fetchHead returns the last commit of this branch, or of the default branch if empty.
+func (s *VCS) fetchHead(ctx context.Context, rawURL string, branch plumbing.ReferenceName) (*vcs.Revision, error) {
+	if !s.ready(ctx) {
+		return nil, errors.ErrSystem
+	}
+	u, err := s.remoteURL(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	release, err := s.limiter.Wait(ctx, u.Host)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	repo, err := git.CloneContext(ctx, memory.NewStorage(), nil, &git.CloneOptions{
+		URL:           u.String(),
+		Auth:          s.basicAuth(u.Host),
+		ReferenceName: branch,
+		SingleBranch:  true,
+		Depth:         1,
+		NoCheckout:    true,
+		Tags:          git.NoTags,
+	})
+	if err != nil {
+		return nil, vcs.Errorf(Name, errors.ErrFetch, err)
+	}
+	head, err := repo.Head()
+	if err != nil {
+		return nil, vcs.Errorf(Name, errors.ErrFetch, err)
+	}
+	c, err := repo.CommitObject(head.Hash())
+	if err != nil {
+		return nil, vcs.Errorf(Name, errors.ErrFetch, err)
+	}
+	return &vcs.Revision{Hash: c.Hash.String(), Time: c.Committer.When.UTC()}, nil
+}
+
+// fetchPath probes each candidate to be the root of the repository until one responds.
+// Only the tags of the module are kept, based on its directory in the repository.
+func (s *VCS) fetchPath(ctx context.Context, path string) (ref *reference) {
+	for _, root := range s.hosts.Roots(path) {
+		ref = s.fetchWithRetry(ctx, root)
+		if ref.err == nil {
+			ref.root = root
+			ref.list = ref.list.Dir(vcs.ModuleDir(root, path))
+			return
+		}
+	}
+	return
+}
+
+func (s *VCS) fetchWithRetry(ctx context.Context, path string) (ref *reference) {
 	for _, t := range []transport{
 		// Secure
 		{scheme: vcs.HTTPS},
@@ -88,7 +211,7 @@ func (s *VCS) fetchWithRetry(path string) (ref *reference) {
 		{scheme: vcs.Git, extension: Ext},
 		{scheme: vcs.HTTP},
 	} {
-		ref = s.fetch(t.rawURL(path))
+		ref = s.fetch(ctx, t.rawURL(path))
 		if ref.err == nil {
 			break
 		}
@@ -96,36 +219,39 @@ func (s *VCS) fetchWithRetry(path string) (ref *reference) {
 	return
 }
 
-func (s *VCS) fetch(rawURL string) *reference {
+// fetch lists the tags of the repository behind this URL.
+// The request waits for the limiter, so the remote host is not flooded, and is retried on temporary failures.
+func (s *VCS) fetch(ctx context.Context, rawURL string) *reference {
 	ref := new(reference)
-	u, err := url.ParseRequestURI(rawURL)
+	u, err := s.remoteURL(rawURL)
 	if err != nil {
-		ref.err = vcs.Errorf(Name, errors.ErrRepository, err)
-		return ref
-	}
-	// Security check
-	if !vcs.IsSecureScheme(u.Scheme) && !s.client.AllowInsecure(vcs.RepoPath(u)) {
-		ref.err = vcs.Errorf(Name, errors.ErrRepository, errors.NewSecurityIssue(u.String()))
+		ref.err = err
 		return ref
 	}
+	ref.url = u.String()
 	rem := git.NewRemote(s.storage, &config.RemoteConfig{
 		Name: "origin",
-		URLs: []string{u.String()},
+		URLs: []string{ref.url},
 	})
 	// Retrieves the releases list of the repository.
-	var (
-		res []*plumbing.Reference
-		rfs = &git.ListOptions{}
-	)
-	if ba := s.auth.BasicAuth(u.Host); ba != nil {
-		rfs.Auth = &http.BasicAuth{
-			Username: ba.Username,
-			Password: ba.Password,
+	var res []*plumbing.Reference
+	err = s.backoff.Retry(ctx, func() error {
+		release, err := s.limiter.Wait(ctx, u.Host)
+		if err != nil {
+			return err
 		}
-	}
-	res, err = rem.List(rfs)
+		defer release()
+		res, err = rem.ListContext(ctx, &git.ListOptions{Auth: s.basicAuth(u.Host)})
+		if err != nil && temporary(err) {
+			return vcs.Temporary(vcs.Errorf(Name, errors.ErrFetch, err))
+		}
+		if err != nil {
+			return vcs.Errorf(Name, errors.ErrFetch, err)
+		}
+		return nil
+	})
 	if err != nil {
-		ref.err = vcs.Errorf(Name, errors.ErrFetch, err)
+		ref.err = err
 		return ref
 	}
 	// Filters to keep only tag.
@@ -139,12 +265,51 @@ func (s *VCS) fetch(rawURL string) *reference {
 	return ref
 }
 
+// temporary returns true if the error is a network failure or an unexpected response of the remote
+// asking to retry later: too many requests or server error.
+func temporary(err error) bool {
+	if vcs.IsTemporary(err) {
+		return true
+	}
+	var ue *plumbing.UnexpectedError
+	if !stderrors.As(err, &ue) {
+		return false
+	}
+	var he *http.Err
+	return stderrors.As(ue.Err, &he) && vcs.IsTemporaryStatus(he.StatusCode())
+}
+
+func (s *VCS) remoteURL(rawURL string) (*url.URL, error) {
+	u, err := url.ParseRequestURI(rawURL)
+	if err != nil {
+		return nil, vcs.Errorf(Name, errors.ErrRepository, err)
+	}
+	// Security check
+	if !vcs.IsSecureScheme(u.Scheme) && !s.client.AllowInsecure(vcs.RepoPath(u)) {
+		return nil, vcs.Errorf(Name, errors.ErrRepository, errors.NewSecurityIssue(u.String()))
+	}
+	return u, nil
+}
+
+func (s *VCS) basicAuth(host string) http.AuthMethod {
+	ba := s.auth.BasicAuth(host)
+	if ba == nil {
+		return nil
+	}
+	return &http.BasicAuth{
+		Username: ba.Username,
+		Password: ba.Password,
+	}
+}
+
 func (s *VCS) ready(ctx context.Context) bool {
 	return ctx != nil && s.storage != nil && s.client != nil && s.auth != nil
 }
 
 type reference struct {
 	list semver.Tags
+	root string
+	url  string
 	err  error
 }
 
@@ -160,21 +325,11 @@ func tags(ctx context.Context, c chan *reference) (semver.Tags, error) {
 	}
 }
 
-const (
-	// example.com/group/pkg, so with 2 slashes: 3 parts.
-	stdNumPart = 3
-	slash      = "/"
-)
-
 type transport struct {
 	scheme    string
 	extension string
 }
 
 func (t transport) rawURL(uri string) string {
-	if p := strings.Split(uri, slash); len(p) > stdNumPart {
-		// Works around with sub-packages.
-		uri = path.Join(p[:stdNumPart]...)
-	}
 	return vcs.URLScheme(t.scheme) + uri + t.extension
 }
@@ -7,10 +7,23 @@ package git
 import (
 	"context"
 	"errors"
+	nethttp "net/http"
+	"os"
+	"path/filepath"
+	"syscall"
 	"testing"
+	"time"
 
+	gogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	gittransport "github.com/go-git/go-git/v5/plumbing/transport"
+	"github.com/go-git/go-git/v5/plumbing/transport/http"
 	"github.com/matryer/is"
 	"github.com/rvflash/goup/internal/vcs"
+	mockvcs "github.com/rvflash/goup/testdata/mock/vcs"
+
+	"go.uber.org/mock/gomock"
 )
 
 const (
@@ -68,5 +81,73 @@ func TestTransport_RawURL(t *testing.T) {
 
 func TestTransport_RawURL2(t *testing.T) {
 	t.Parallel()
-	is.New(t).Equal(transport{}.rawURL(subRepo), repo)
+	is.New(t).Equal(transport{}.rawURL(subRepo), subRepo)
+}
+
+func TestTemporary(t *testing.T) {
+	t.Parallel()
+	var (
+		are    = is.New(t)
+		status = func(code int) error {
+			return plumbing.NewUnexpectedError(&http.Err{Response: &nethttp.Response{StatusCode: code}})
+		}
+		dt = map[string]struct {
+			in  error
+			out bool
+		}{
+			"default":      {},
+			"not found":    {in: gittransport.ErrRepositoryNotFound},
+			"bad request":  {in: status(nethttp.StatusBadRequest)},
+			"too many":     {in: status(nethttp.StatusTooManyRequests), out: true},
+			"server error": {in: status(nethttp.StatusBadGateway), out: true},
+			"reset":        {in: syscall.ECONNRESET, out: true},
+		}
+	)
+	for name, ts := range dt {
+		tt := ts
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			are.Equal(temporary(tt.in), tt.out) // mismatch result
+		})
+	}
+}
+
+func TestVCS_FetchHead_Branch(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	var (
+		are = is.New(t)
+		dir = t.TempDir()
+		cli = mockvcs.NewMockClientChooser(ctrl)
+		ath = mockvcs.NewMockBasicAuthentifier(ctrl)
+	)
+	cli.EXPECT().AllowInsecure(gomock.Any()).Return(true).AnyTimes()
+	ath.EXPECT().BasicAuth(gomock.Any()).Return(nil).AnyTimes()
+	r, err := gogit.PlainInit(dir, false)
+	are.NoErr(err) // unexpected error
+	wt, err := r.Worktree()
+	are.NoErr(err) // unexpected error
+	commit := func(content string) plumbing.Hash {
+		err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte(content), 0o600)
+		are.NoErr(err) // unexpected error
+		_, err = wt.Add("go.mod")
+		are.NoErr(err) // unexpected error
+		sig := &object.Signature{Name: "goup", Email: "goup@example.com", When: time.Now()}
+		h, err := wt.Commit(content, &gogit.CommitOptions{Author: sig})
+		are.NoErr(err) // unexpected error
+		return h
+	}
+	// The v2 branch stays behind the default one, used by the next major.
+	v2 := commit("module gopkg.in/pkg.v2\n")
+	are.NoErr(r.Storer.SetReference(plumbing.NewHashReference(plumbing.NewBranchReferenceName("v2"), v2)))
+	v3 := commit("module gopkg.in/pkg.v3\n")
+
+	s := New(cli, ath)
+	res, err := s.fetchHead(context.Background(), "file://"+dir, plumbing.NewBranchReferenceName("v2"))
+	are.NoErr(err)                   // unexpected error
+	are.Equal(res.Hash, v2.String()) // mismatch commit of the branch
+	res, err = s.fetchHead(context.Background(), "file://"+dir, "")
+	are.NoErr(err)                   // unexpected error
+	are.Equal(res.Hash, v3.String()) // mismatch commit of the default branch
 }
@@ -7,9 +7,13 @@ package git_test
 import (
 	"context"
 	"errors"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
+	gogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
 	"github.com/matryer/is"
 	errup "github.com/rvflash/goup/internal/errors"
 	"github.com/rvflash/goup/internal/vcs"
@@ -133,6 +137,75 @@ func TestVCS_FetchURL(t *testing.T) {
 	}
 }
 
+func TestVCS_FetchHeadURL(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	var (
+		are = is.New(t)
+		dir = newRepository(t)
+		cli = mockvcs.NewMockClientChooser(ctrl)
+		ath = mockvcs.NewMockBasicAuthentifier(ctrl)
+	)
+	cli.EXPECT().AllowInsecure(gomock.Any()).Return(true).AnyTimes()
+	ath.EXPECT().BasicAuth(gomock.Any()).Return(nil).AnyTimes()
+	repo, err := gogit.PlainOpen(dir)
+	are.NoErr(err) // unexpected error
+	head, err := repo.Head()
+	are.NoErr(err) // unexpected error
+
+	s := git.New(cli, ath)
+	res, err := s.FetchHeadURL(context.Background(), "file://"+dir)
+	are.NoErr(err)                            // unexpected error
+	are.Equal(res.Hash, head.Hash().String()) // mismatch commit
+	are.True(!res.Time.IsZero())              // mismatch time
+	_, err = s.FetchHeadURL(context.Background(), "file://"+t.TempDir())
+	are.True(errors.Is(err, errup.ErrFetch)) // mismatch error
+}
+
+// newRepository creates a local repository with a root module and another in the sub directory.
+func newRepository(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	repo, err := gogit.PlainInit(dir, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wt, err := repo.Worktree()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, content := range map[string]string{
+		"go.mod":     "module example.com/pkg\n",
+		"sub/go.mod": "module example.com/pkg/sub\n",
+	} {
+		if err = os.MkdirAll(filepath.Join(dir, filepath.Dir(name)), 0o700); err != nil {
+			t.Fatal(err)
+		}
+		if err = os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if _, err = wt.Add(name); err != nil {
+			t.Fatal(err)
+		}
+	}
+	sig := &object.Signature{Name: "goup", Email: "goup@example.com", When: time.Now()}
+	h, err := wt.Commit("init", &gogit.CommitOptions{Author: sig})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for tag, opts := range map[string]*gogit.CreateTagOptions{
+		"v1.0.0":     nil,
+		"v1.1.0":     {Tagger: sig, Message: "v1.1.0"},
+		"sub/v0.1.0": nil,
+	} {
+		if _, err = repo.CreateTag(tag, h, opts); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
 func newMockClientChooser(ctrl *gomock.Controller) *mockvcs.MockClientChooser {
 	c := mockvcs.NewMockClientChooser(ctrl)
 	c.EXPECT().AllowInsecure(pkgName).Return(false).AnyTimes()
@@ -8,6 +8,7 @@ package goget
 import (
 	"context"
 	"encoding/xml"
+	"fmt"
 	"io"
 	"net/http"
 	"net/url"
@@ -17,6 +18,9 @@ import (
 	"github.com/rvflash/goup/internal/semver"
 	"github.com/rvflash/goup/internal/vcs"
 	"github.com/rvflash/goup/internal/vcs/git"
+	"github.com/rvflash/goup/internal/vcs/goproxy"
+
+	"golang.org/x/mod/module"
 )
 
 // Name is the name of this VCS.
@@ -24,81 +28,275 @@ const Name = "go-get"
 
 // VCS is a go-get version control system.
 // We use go-get to retrieve the remote's properties behind a package.
+// The tags are listed by the system registered for the VCS declared in the go-import metadata.
 type VCS struct {
-	http vcs.ClientChooser
-	git  vcs.System
+	http    vcs.ClientChooser
+	systems map[string]vcs.System
+	cache   vcs.Cache
+	prefix  string
+	hosts   vcs.Hosts
+	backoff vcs.Backoff
 }
 
-// New creates a new instance of VCS.
-func New(client vcs.ClientChooser, git vcs.System) *VCS {
-	return &VCS{
-		http: client,
-		git:  git,
+// Option allows to customize the VCS.
+type Option func(s *VCS)
+
+// WithCache defines the cache used to store the go-import metadata.
+func WithCache(c vcs.Cache) Option {
+	return func(s *VCS) {
+		s.cache = c
 	}
 }
 
-// CanFetch implements the vcs.VCS interface.
-func (s *VCS) CanFetch(path string) bool {
-	if path == "" {
-		return false
+// WithNamespace adds this namespace to the prefix of the cache keys,
+// like the settings changing the go-import metadata served.
+func WithNamespace(namespace string) Option {
+	return func(s *VCS) {
+		if namespace != "" {
+			s.prefix = Name + " " + namespace
+		}
+	}
+}
+
+// WithBackoff defines the policy used to retry the requests of go-import metadata failed with a temporary error.
+// By default, there is no retry.
+func WithBackoff(b vcs.Backoff) Option {
+	return func(s *VCS) {
+		s.backoff = b
+	}
+}
+
+// WithHosts defines the registry of hosts with a known layout.
+// The modules of these hosts are directly fetched with their VCS, without go-import metadata.
+// By default, only the layouts of the well-known hosts are known.
+func WithHosts(h vcs.Hosts) Option {
+	return func(s *VCS) {
+		s.hosts = h
 	}
-	for _, service := range []string{"github.com", "gitlab", "bitbucket"} {
-		if strings.Contains(path, service) {
-			return false
+}
+
+// WithSystem registers the system used to list the tags of the repositories using this VCS,
+// like hg or svn. With mod, the system must list the versions of a module on a Go module proxy.
+func WithSystem(name string, system vcs.System) Option {
+	return func(s *VCS) {
+		if system != nil {
+			s.systems[name] = system
 		}
 	}
-	return true
+}
+
+// New creates a new instance of VCS.
+// The git system is registered by default.
+func New(client vcs.ClientChooser, gitVCS vcs.System, opts ...Option) *VCS {
+	s := &VCS{
+		http:    client,
+		systems: make(map[string]vcs.System),
+		prefix:  Name,
+		hosts:   vcs.DefaultHosts,
+	}
+	opts = append([]Option{WithSystem(git.Name, gitVCS)}, opts...)
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// CanFetch implements the vcs.VCS interface.
+func (s *VCS) CanFetch(path string) bool {
+	return path != ""
 }
 
 // FetchPath implements the vcs.VCS interface.
+// The root of the repositories of the hosts with a known layout does not need to be discovered,
+// so as without go-import metadata, it returns errors.ErrDirect to fetch them with their VCS.
 func (s *VCS) FetchPath(ctx context.Context, path string) (semver.Tags, error) {
-	system, remote, err := s.vcsByPath(ctx, path)
+	if path == "" {
+		return nil, errors.ErrRepository
+	}
+	if _, ok := s.hosts.Root(path); ok {
+		return nil, vcs.Errorf(Name, errors.ErrDirect)
+	}
+	m, err := s.vcsByPath(ctx, path)
 	if err != nil {
 		return nil, err
 	}
-	return s.fetchURL(ctx, system, remote)
+	return s.fetchURL(ctx, m, path)
 }
 
 // FetchURL implements the vcs.VCS interface.
-func (s *VCS) FetchURL(ctx context.Context, url string) (semver.Tags, error) {
-	system, remote, err := s.vcsByURL(ctx, url)
+func (s *VCS) FetchURL(ctx context.Context, rawURL string) (semver.Tags, error) {
+	m, err := s.vcsByURL(ctx, rawURL)
 	if err != nil {
 		return nil, err
 	}
-	return s.fetchURL(ctx, system, remote)
+	var path string
+	if u, err := url.Parse(rawURL); err == nil {
+		path = vcs.RepoPath(u)
+	}
+	return s.fetchURL(ctx, m, path)
 }
 
-func (s *VCS) fetchURL(ctx context.Context, system, url string) (semver.Tags, error) {
-	if s.git == nil {
-		return nil, errors.ErrSystem
+// FetchMod implements the vcs.ModFetcher interface.
+// As with FetchPath, it returns errors.ErrDirect when the go-import metadata is not required.
+func (s *VCS) FetchMod(ctx context.Context, path, version string) ([]byte, error) {
+	if path == "" {
+		return nil, errors.ErrRepository
 	}
-	switch system {
-	case git.Name:
-		return s.git.FetchURL(ctx, url)
-	default:
-		return nil, vcs.Errorf(system, errors.ErrSystem)
+	if _, ok := s.hosts.Root(path); ok {
+		return nil, vcs.Errorf(Name, errors.ErrDirect)
+	}
+	m, err := s.vcsByPath(ctx, path)
+	if err != nil {
+		return nil, err
 	}
+	return s.fetchMod(ctx, m, path, version)
 }
 
-func (s *VCS) vcsByPath(ctx context.Context, path string) (name, remote string, err error) {
+// FetchModURL implements the vcs.ModFetcher interface.
+// The directory is ignored, the one of the module is deduced from the go-import metadata.
+func (s *VCS) FetchModURL(ctx context.Context, rawURL, _, version string) ([]byte, error) {
+	m, err := s.vcsByURL(ctx, rawURL)
+	if err != nil {
+		return nil, err
+	}
+	var path string
+	if u, err := url.Parse(rawURL); err == nil {
+		path = vcs.RepoPath(u)
+	}
+	return s.fetchMod(ctx, m, path, version)
+}
+
+// FetchHead implements the vcs.HeadFetcher interface.
+// As with FetchPath, it returns errors.ErrDirect when the go-import metadata is not required.
+func (s *VCS) FetchHead(ctx context.Context, path string) (*vcs.Revision, error) {
 	if path == "" {
-		return "", "", errors.ErrRepository
+		return nil, errors.ErrRepository
 	}
-	for _, scheme := range []string{vcs.HTTPS, vcs.HTTP} {
-		name, remote, err = s.vcsByURL(ctx, vcs.URLScheme(scheme)+path)
-		if err == nil {
-			break
+	if _, ok := s.hosts.Root(path); ok {
+		return nil, vcs.Errorf(Name, errors.ErrDirect)
+	}
+	m, err := s.vcsByPath(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	return s.fetchHead(ctx, m)
+}
+
+// FetchHeadURL implements the vcs.HeadFetcher interface.
+func (s *VCS) FetchHeadURL(ctx context.Context, rawURL string) (*vcs.Revision, error) {
+	m, err := s.vcsByURL(ctx, rawURL)
+	if err != nil {
+		return nil, err
+	}
+	return s.fetchHead(ctx, m)
+}
+
+// fetchHead reads the last commit of the repository declared in the metadata.
+// A Go module proxy has no branch, so it is not supported.
+func (s *VCS) fetchHead(ctx context.Context, m metaGoImport) (*vcs.Revision, error) {
+	system, ok := s.systems[m.VCS].(vcs.HeadFetcher)
+	if !ok || m.VCS == goproxy.Name {
+		return nil, vcs.Errorf(m.VCS, errors.ErrSystem)
+	}
+	return system.FetchHeadURL(ctx, m.URL)
+}
+
+// fetchMod reads the go.mod file of the module version with the system declared in the metadata.
+// Only a Go module proxy serves it, a repository is never cloned to read one file.
+func (s *VCS) fetchMod(ctx context.Context, m metaGoImport, path, version string) ([]byte, error) {
+	system, ok := s.systems[m.VCS].(vcs.ModFetcher)
+	if !ok {
+		return nil, vcs.Errorf(m.VCS, errors.ErrSystem)
+	}
+	if m.VCS == goproxy.Name {
+		u, err := proxyURL(m, path)
+		if err != nil {
+			return nil, err
 		}
+		return system.FetchModURL(ctx, u, "", version)
+	}
+	return system.FetchModURL(ctx, m.URL, vcs.ModuleDir(m.Prefix, path), version)
+}
+
+func (s *VCS) fetchURL(ctx context.Context, m metaGoImport, path string) (semver.Tags, error) {
+	system, ok := s.systems[m.VCS]
+	if !ok {
+		return nil, vcs.Errorf(m.VCS, errors.ErrSystem)
+	}
+	if m.VCS == goproxy.Name {
+		u, err := proxyURL(m, path)
+		if err != nil {
+			return nil, err
+		}
+		return system.FetchURL(ctx, u)
+	}
+	res, err := system.FetchURL(ctx, m.URL)
+	if err != nil {
+		return nil, err
+	}
+	// The prefix of the go-import meta tag is the root path of the repository.
+	return res.Dir(vcs.ModuleDir(m.Prefix, path)), nil
+}
+
+// proxyURL returns the URL of the module behind this path on the Go module proxy declared in the metadata.
+func proxyURL(m metaGoImport, path string) (string, error) {
+	if path == "" {
+		path = m.Prefix
+	}
+	p, err := module.EscapePath(path)
+	if err != nil {
+		return "", vcs.Errorf(goproxy.Name, errors.ErrRepository, err)
+	}
+	return strings.TrimSuffix(m.URL, "/") + "/" + p, nil
+}
+
+// vcsByPath returns the go-import metadata of this path, served over https or, if allowed, over http.
+// Only the lack of metadata returns errors.ErrDirect, a temporary or a context error is returned unchanged.
+func (s *VCS) vcsByPath(ctx context.Context, path string) (m metaGoImport, err error) {
+	if path == "" {
+		return m, errors.ErrRepository
+	}
+	m, err = s.vcsByURL(ctx, vcs.URLScheme(vcs.HTTPS)+path)
+	if err == nil || ctx.Err() != nil || vcs.IsTemporary(err) || s.http == nil || !s.http.AllowInsecure(path) {
+		return m, err
+	}
+	// The failure of the secure request is kept if the insecure one fails too.
+	if w, err2 := s.vcsByURL(ctx, vcs.URLScheme(vcs.HTTP)+path); err2 == nil {
+		return w, nil
+	}
+	return m, err
+}
+
+// metaGoImport represents a go-import meta tag.
+type metaGoImport struct {
+	Prefix string `json:"prefix"`
+	VCS    string `json:"vcs"`
+	URL    string `json:"url"`
+}
+
+// vcsByURL returns the go-import metadata behind this URL.
+// The request is retried on temporary failures.
+func (s *VCS) vcsByURL(ctx context.Context, url string) (m metaGoImport, err error) {
+	key := s.prefix + " " + url
+	if s.cache != nil && s.cache.Get(key, &m) {
+		return m, nil
+	}
+	err = s.backoff.Retry(ctx, func() (err error) {
+		m, err = s.fetchMeta(ctx, url)
+		return err
+	})
+	if s.cache != nil && err == nil && m.VCS != "" {
+		_ = s.cache.Set(key, m)
 	}
 	return
 }
 
-func (s *VCS) vcsByURL(ctx context.Context, url string) (name, remote string, err error) {
+func (s *VCS) fetchMeta(ctx context.Context, url string) (m metaGoImport, err error) {
 	if ctx == nil || s.http == nil {
-		return "", "", errors.ErrSystem
+		return m, errors.ErrSystem
 	}
 	if url == "" {
-		return "", "", errors.ErrRepository
+		return m, errors.ErrRepository
 	}
 	var req *http.Request
 	req, err = http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
@@ -109,16 +307,28 @@ func (s *VCS) vcsByURL(ctx context.Context, url string) (name, remote string, er
 
 	// Security check
 	if !vcs.IsSecureScheme(req.URL.Scheme) && !s.http.AllowInsecure(vcs.RepoPath(req.URL)) {
-		return "", "", errors.NewSecurityIssue(req.URL.String())
+		return m, errors.NewSecurityIssue(req.URL.String())
 	}
 	var resp *http.Response
 	resp, err = s.http.ClientFor(vcs.RepoPath(req.URL)).Do(req)
 	if err != nil {
+		if vcs.IsTemporary(err) {
+			err = vcs.Temporary(err)
+		}
 		return
 	}
 	defer func() { _ = resp.Body.Close() }()
-
-	return parseMetaGoImport(resp.Body)
+	if vcs.IsTemporaryStatus(resp.StatusCode) {
+		return m, vcs.Temporary(vcs.Errorf(Name, errors.ErrFetch, fmt.Sprintf("%s: %s", req.URL, resp.Status)))
+	}
+	// Without go-import metadata, the repository is fetched with its VCS.
+	if resp.StatusCode != http.StatusOK {
+		return m, vcs.Errorf(Name, errors.ErrDirect, fmt.Sprintf("%s: %s", req.URL, resp.Status))
+	}
+	if m, _ = parseMetaGoImport(resp.Body); m.VCS == "" {
+		return m, vcs.Errorf(Name, errors.ErrDirect, "no go-import meta tag: "+req.URL.String())
+	}
+	return m, nil
 }
 
 const (
@@ -134,7 +344,7 @@ const (
 	ascii = "ascii"
 )
 
-func parseMetaGoImport(r io.Reader) (vcs, url string, err error) {
+func parseMetaGoImport(r io.Reader) (m metaGoImport, err error) {
 	d := xml.NewDecoder(r)
 	d.CharsetReader = charsetReader
 	d.Strict = false
@@ -158,8 +368,7 @@ func parseMetaGoImport(r io.Reader) (vcs, url string, err error) {
 			continue
 		}
 		if f := strings.Fields(attrValue(e.Attr, content)); len(f) == 3 {
-			vcs = f[1]
-			url = f[2]
+			m = metaGoImport{Prefix: f[0], VCS: f[1], URL: f[2]}
 			break
 		}
 	}
@@ -21,6 +21,7 @@ func TestParseMetaGoImport(t *testing.T) {
 		are = is.New(t)
 		dt  = map[string]struct {
 			path   []string
+			prefix string
 			system string
 			uri    string
 			err    error
@@ -31,11 +32,13 @@ func TestParseMetaGoImport(t *testing.T) {
 			},
 			"default": {
 				path:   []string{"..", "..", "..", "testdata", "golden", "goget", "default.html"},
+				prefix: "golang.org/x/mod",
 				system: "git",
 				uri:    "https://go.googlesource.com/mod",
 			},
 			"no-charset": {
 				path:   []string{"..", "..", "..", "testdata", "golden", "goget", "no-charset.html"},
+				prefix: "google.golang.org/appengine",
 				system: "git",
 				uri:    "https://github.com/golang/appengine",
 			},
@@ -49,10 +52,11 @@ func TestParseMetaGoImport(t *testing.T) {
 			if err != nil {
 				t.Fatal(err)
 			}
-			sys, uri, err := parseMetaGoImport(f)
+			m, err := parseMetaGoImport(f)
 			are.True(errors.Is(err, tt.err)) // mismatch error
-			are.Equal(uri, tt.uri)           // mismatch url
-			are.Equal(sys, tt.system)        // mismatch system
+			are.Equal(m.Prefix, tt.prefix)   // mismatch prefix
+			are.Equal(m.URL, tt.uri)         // mismatch url
+			are.Equal(m.VCS, tt.system)      // mismatch system
 		})
 	}
 }
@@ -6,15 +6,18 @@ package goget_test
 
 import (
 	"context"
+	stderrors "errors"
 	"net/http"
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/matryer/is"
 	"github.com/rvflash/goup/internal/errors"
 	"github.com/rvflash/goup/internal/semver"
 	"github.com/rvflash/goup/internal/vcs"
+	"github.com/rvflash/goup/internal/vcs/cache"
 	"github.com/rvflash/goup/internal/vcs/goget"
 	mockvcs "github.com/rvflash/goup/testdata/mock/vcs"
 
@@ -37,13 +40,13 @@ func TestVCS_CanFetch(t *testing.T) {
 			in  string
 			out bool
 		}{
-			"Default":               {in: ""},
-			"Ignore Bitbucket":      {in: "bitbucket.org/repo/all"},
-			"Ignore private Gitlab": {in: "gitlab.example.lan/group/pkg"},
-			"Ignore public Gitlab":  {in: "gitlab.com/group/pkg"},
-			"Ignore Github":         {in: "github.com/golang/mock"},
-			"Incomplete":            {in: "golang.org", out: true},
-			"Ok":                    {in: pkgName, out: true},
+			"Default":        {in: ""},
+			"Bitbucket":      {in: "bitbucket.org/repo/all", out: true},
+			"Private Gitlab": {in: "gitlab.example.lan/group/sub/pkg", out: true},
+			"Public Gitlab":  {in: "gitlab.com/group/pkg", out: true},
+			"Github":         {in: "github.com/golang/mock", out: true},
+			"Incomplete":     {in: "golang.org", out: true},
+			"Ok":             {in: pkgName, out: true},
 		}
 	)
 	for name, ts := range dt {
@@ -66,6 +69,7 @@ func TestVCS_FetchPath(t *testing.T) {
 			git  vcs.System
 			ctx  context.Context
 			path string
+			opts []goget.Option
 			res  semver.Tags
 			err  error
 		}{
@@ -83,6 +87,56 @@ func TestVCS_FetchPath(t *testing.T) {
 				ctx: context.Background(),
 				err: errors.ErrRepository,
 			},
+			"known host": {
+				cli:  mockvcs.NewMockClientChooser(ctrl),
+				git:  mockvcs.NewMockSystem(ctrl),
+				ctx:  context.Background(),
+				path: "github.com/rvflash/goup",
+				err:  errors.ErrDirect,
+			},
+			"gopkg.in": {
+				cli:  mockvcs.NewMockClientChooser(ctrl),
+				git:  mockvcs.NewMockSystem(ctrl),
+				ctx:  context.Background(),
+				path: "gopkg.in/yaml.v3",
+				err:  errors.ErrDirect,
+			},
+			"custom host": {
+				cli:  mockvcs.NewMockClientChooser(ctrl),
+				git:  mockvcs.NewMockSystem(ctrl),
+				ctx:  context.Background(),
+				path: "golang.org/x/mod/sub",
+				err:  errors.ErrDirect,
+				opts: []goget.Option{goget.WithHosts(vcs.NewHosts("golang.org/x/*"))},
+			},
+			"client failure": {
+				cli:  newSecureClientChooser(ctrl, errors.ErrFetch),
+				git:  mockvcs.NewMockSystem(ctrl),
+				ctx:  context.Background(),
+				path: pkgName,
+				err:  errors.ErrFetch,
+			},
+			"deadline exceeded": {
+				cli:  newSecureClientChooser(ctrl, context.DeadlineExceeded),
+				git:  mockvcs.NewMockSystem(ctrl),
+				ctx:  context.Background(),
+				path: pkgName,
+				err:  context.DeadlineExceeded,
+			},
+			"no meta tag": {
+				cli:  newPageClientChooser(ctrl, "no-meta.html", http.StatusOK),
+				git:  mockvcs.NewMockSystem(ctrl),
+				ctx:  context.Background(),
+				path: pkgName,
+				err:  errors.ErrDirect,
+			},
+			"not found page": {
+				cli:  newPageClientChooser(ctrl, "no-meta.html", http.StatusNotFound),
+				git:  mockvcs.NewMockSystem(ctrl),
+				ctx:  context.Background(),
+				path: pkgName,
+				err:  errors.ErrDirect,
+			},
 			"ok": {
 				cli:  newMockClientChooser(ctrl, nil),
 				git:  newMockSystem(ctrl),
@@ -95,10 +149,10 @@ func TestVCS_FetchPath(t *testing.T) {
 	for name, ts := range dt {
 		tt := ts
 		t.Run(name, func(_ *testing.T) {
-			s := goget.New(tt.cli, tt.git)
+			s := goget.New(tt.cli, tt.git, tt.opts...)
 			res, err := s.FetchPath(tt.ctx, tt.path)
-			are.Equal(err, tt.err) // mismatch error
-			are.Equal(res, tt.res) // mismatch result
+			are.True(stderrors.Is(err, tt.err)) // mismatch error
+			are.Equal(res, tt.res)              // mismatch result
 		})
 	}
 }
@@ -144,6 +198,13 @@ func TestVCS_FetchURL(t *testing.T) {
 				uri: repoURL,
 				err: errors.ErrFetch,
 			},
+			"no meta tag": {
+				cli: newPageClientChooser(ctrl, "no-meta.html", http.StatusOK),
+				git: mockvcs.NewMockSystem(ctrl),
+				ctx: context.Background(),
+				uri: repoURL,
+				err: errors.ErrDirect,
+			},
 			"ok": {
 				cli: newMockClientChooser(ctrl, nil),
 				git: newMockSystem(ctrl),
@@ -158,8 +219,8 @@ func TestVCS_FetchURL(t *testing.T) {
 		t.Run(name, func(_ *testing.T) {
 			s := goget.New(tt.cli, tt.git)
 			res, err := s.FetchURL(tt.ctx, tt.uri)
-			are.Equal(err, tt.err) // mismatch error
-			are.Equal(res, tt.res) // mismatch result
+			are.True(stderrors.Is(err, tt.err)) // mismatch error
+			are.Equal(res, tt.res)              // mismatch result
 		})
 	}
 }
@@ -179,22 +240,56 @@ func newMockClientChooser(ctrl *gomock.Controller, err error) *mockvcs.MockClien
 	return c
 }
 
+func newSecureClientChooser(ctrl *gomock.Controller, err error) *mockvcs.MockClientChooser {
+	c := newMockClientChooser(ctrl, err)
+	c.EXPECT().AllowInsecure(gomock.Any()).Return(false).AnyTimes()
+	return c
+}
+
+// newPageClientChooser returns a client serving this page with this status code, whatever the scheme.
+func newPageClientChooser(ctrl *gomock.Controller, file string, status int) *mockvcs.MockClientChooser {
+	c := mockvcs.NewMockClientChooser(ctrl)
+	c.EXPECT().ClientFor(gomock.Any()).Return(&mockClient{file: file, status: status}).AnyTimes()
+	c.EXPECT().AllowInsecure(gomock.Any()).Return(true).AnyTimes()
+	return c
+}
+
 type mockClient struct {
-	err error
+	err         error
+	file        string
+	status      int
+	unavailable int
 }
 
 func (c *mockClient) Do(req *http.Request) (*http.Response, error) {
 	if c.err != nil {
 		return nil, c.err
 	}
-	name := []string{"..", "..", "..", "testdata", "golden", "goget", "default.html"}
+	if c.unavailable > 0 {
+		c.unavailable--
+		return &http.Response{
+			Status:     http.StatusText(http.StatusServiceUnavailable),
+			StatusCode: http.StatusServiceUnavailable,
+			Body:       http.NoBody,
+			Request:    req,
+		}, nil
+	}
+	file := c.file
+	if file == "" {
+		file = "default.html"
+	}
+	name := []string{"..", "..", "..", "testdata", "golden", "goget", file}
 	b, err := os.Open(filepath.Join(name...))
 	if err != nil {
 		return nil, err
 	}
+	status := c.status
+	if status == 0 {
+		status = http.StatusOK
+	}
 	resp := &http.Response{
-		Status:     http.StatusText(http.StatusOK),
-		StatusCode: http.StatusOK,
+		Status:     http.StatusText(status),
+		StatusCode: status,
 		Body:       b,
 		Request:    req,
 	}
@@ -209,3 +304,159 @@ func newMockSystem(ctrl *gomock.Controller) *mockvcs.MockSystem {
 	c.EXPECT().FetchURL(gomock.Any(), repoURL).Return(semver.Tags{v}, nil).Times(oneTime)
 	return c
 }
+
+func TestWithCache(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	are := is.New(t)
+	store, err := cache.Open(t.TempDir(), time.Hour)
+	are.NoErr(err) // unexpected error
+
+	git := mockvcs.NewMockSystem(ctrl)
+	git.EXPECT().FetchURL(gomock.Any(), repoURL).Return(semver.Tags{semver.New(tagValue)}, nil).Times(2)
+
+	// The client must only be called once: the go-import metadata is then read from the cache.
+	s := goget.New(newMockClientChooser(ctrl, nil), git, goget.WithCache(store))
+	for i := 0; i < 2; i++ {
+		res, err := s.FetchURL(context.Background(), repoURL)
+		are.NoErr(err)                                    // mismatch error
+		are.Equal(res, semver.Tags{semver.New(tagValue)}) // mismatch result
+	}
+}
+
+func TestWithNamespace(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	are := is.New(t)
+	store, err := cache.Open(t.TempDir(), time.Hour)
+	are.NoErr(err) // unexpected error
+
+	git := mockvcs.NewMockSystem(ctrl)
+	git.EXPECT().FetchURL(gomock.Any(), repoURL).Return(semver.Tags{semver.New(tagValue)}, nil).Times(3)
+
+	// The go-import metadata is requested once by namespace.
+	cli := mockvcs.NewMockClientChooser(ctrl)
+	cli.EXPECT().ClientFor(gomock.Any()).Return(&mockClient{}).Times(2)
+	for _, ns := range []string{"", "insecure", ""} {
+		res, err := goget.New(cli, git, goget.WithCache(store), goget.WithNamespace(ns)).FetchURL(context.Background(), repoURL)
+		are.NoErr(err)                                    // mismatch error
+		are.Equal(res, semver.Tags{semver.New(tagValue)}) // mismatch result
+	}
+}
+
+func TestWithBackoff(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	var (
+		are = is.New(t)
+		b   = vcs.Backoff{Attempts: 3, Base: time.Millisecond}
+		cli = mockvcs.NewMockClientChooser(ctrl)
+		git = mockvcs.NewMockSystem(ctrl)
+		ctx = vcs.WithRetries(context.Background())
+	)
+	// The server is unavailable twice, then responds.
+	cli.EXPECT().ClientFor(gomock.Any()).Return(&mockClient{unavailable: 2}).Times(3)
+	git.EXPECT().FetchURL(gomock.Any(), repoURL).Return(semver.Tags{semver.New(tagValue)}, nil).Times(oneTime)
+
+	res, err := goget.New(cli, git, goget.WithBackoff(b)).FetchURL(ctx, repoURL)
+	are.NoErr(err)                                    // unexpected error
+	are.Equal(res, semver.Tags{semver.New(tagValue)}) // mismatch result
+	are.Equal(vcs.Retries(ctx), 2)                    // mismatch retries
+}
+
+func TestVCS_FetchPath_Dir(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	var (
+		are  = is.New(t)
+		git  = mockvcs.NewMockSystem(ctrl)
+		tags = semver.Tags{semver.New(tagValue), semver.New("sub/v0.0.1"), semver.New("sub/dir/v0.0.2")}
+	)
+	git.EXPECT().FetchURL(gomock.Any(), repoURL).Return(tags, nil).Times(oneTime)
+
+	// The go-import meta tag defines golang.org/x/mod as the root of the repository.
+	s := goget.New(newMockClientChooser(ctrl, nil), git)
+	res, err := s.FetchPath(context.Background(), pkgName+"/sub")
+	are.NoErr(err)                                        // unexpected error
+	are.Equal(res, semver.Tags{semver.New("sub/v0.0.1")}) // mismatch result
+}
+
+func TestWithSystem(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	var (
+		are  = is.New(t)
+		cli  = mockvcs.NewMockClientChooser(ctrl)
+		mod  = mockvcs.NewMockSystem(ctrl)
+		tags = semver.Tags{semver.New(tagValue)}
+	)
+	cli.EXPECT().ClientFor(gomock.Any()).Return(&mockClient{file: "mod.html"}).Times(oneTime)
+	// The module path is escaped to request the Go module proxy.
+	mod.EXPECT().FetchURL(gomock.Any(), "https://proxy.example.com/example.com/!mod").Return(tags, nil).Times(oneTime)
+
+	s := goget.New(cli, nil, goget.WithSystem("mod", mod))
+	res, err := s.FetchPath(context.Background(), "example.com/Mod")
+	are.NoErr(err)       // unexpected error
+	are.Equal(res, tags) // mismatch result
+}
+
+func TestVCS_FetchMod(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	var (
+		are = is.New(t)
+		git = struct {
+			*mockvcs.MockSystem
+			*mockvcs.MockModFetcher
+		}{
+			MockSystem:     mockvcs.NewMockSystem(ctrl),
+			MockModFetcher: mockvcs.NewMockModFetcher(ctrl),
+		}
+		content = []byte("module " + pkgName + "/sub\n")
+	)
+	// The directory of the module in the repository is deduced from the go-import meta tag.
+	git.MockModFetcher.EXPECT().FetchModURL(gomock.Any(), repoURL, "sub", tagValue).Return(content, nil).Times(oneTime)
+
+	s := goget.New(newMockClientChooser(ctrl, nil), git)
+	res, err := s.FetchMod(context.Background(), pkgName+"/sub", tagValue)
+	are.NoErr(err)          // unexpected error
+	are.Equal(res, content) // mismatch result
+
+	// The root of the repositories of the hosts with a known layout is not discovered.
+	_, err = s.FetchMod(context.Background(), "github.com/rvflash/goup", tagValue)
+	are.True(stderrors.Is(err, errors.ErrDirect)) // mismatch error
+}
+
+func TestVCS_FetchHead(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	var (
+		are = is.New(t)
+		git = struct {
+			*mockvcs.MockSystem
+			*mockvcs.MockHeadFetcher
+		}{
+			MockSystem:      mockvcs.NewMockSystem(ctrl),
+			MockHeadFetcher: mockvcs.NewMockHeadFetcher(ctrl),
+		}
+		head = &vcs.Revision{Hash: "accd165b1659", Time: time.Date(2020, 1, 21, 19, 2, 30, 0, time.UTC)}
+	)
+	// The last commit is read on the repository declared in the go-import meta tag.
+	git.MockHeadFetcher.EXPECT().FetchHeadURL(gomock.Any(), repoURL).Return(head, nil).Times(oneTime)
+
+	s := goget.New(newMockClientChooser(ctrl, nil), git)
+	res, err := s.FetchHead(context.Background(), pkgName+"/sub")
+	are.NoErr(err)       // unexpected error
+	are.Equal(res, head) // mismatch result
+
+	// The root of the repositories of the hosts with a known layout is not discovered.
+	_, err = s.FetchHead(context.Background(), "github.com/rvflash/goup")
+	are.True(stderrors.Is(err, errors.ErrDirect)) // mismatch error
+}
@@ -0,0 +1,301 @@
+// Copyright (c) 2020 Hervé Gouchet. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+// Package goproxy provides methods to deal with a Go module proxy as VCS.
+// See https://go.dev/ref/mod#goproxy-protocol.
+package goproxy
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"time"
+
+	errs "github.com/rvflash/goup/internal/errors"
+	"github.com/rvflash/goup/internal/path"
+	"github.com/rvflash/goup/internal/semver"
+	"github.com/rvflash/goup/internal/vcs"
+
+	"golang.org/x/mod/module"
+)
+
+const (
+	// Name is the name of this VCS.
+	Name = "mod"
+	// Direct is the keyword used in the proxy list to fall back on a direct access to the VCS.
+	Direct = "direct"
+	// Off is the keyword used in the proxy list to disallow any access.
+	Off = "off"
+	// DefaultList is the proxy list used by the go command without GOPROXY.
+	DefaultList = "https://proxy.golang.org,direct"
+)
+
+// VCS is a version control system behind one or more Go module proxies.
+type VCS struct {
+	auth    vcs.BasicAuthentifier
+	http    vcs.ClientChooser
+	proxies []proxy
+	noProxy string
+}
+
+// New returns a new instance of VCS.
+// proxyList is a GOPROXY value and noProxy a comma-separated list of glob patterns (GONOPROXY).
+func New(client vcs.ClientChooser, auth vcs.BasicAuthentifier, proxyList, noProxy string) *VCS {
+	return &VCS{
+		auth:    auth,
+		http:    client,
+		proxies: parseList(proxyList),
+		noProxy: noProxy,
+	}
+}
+
+// CanFetch implements the vcs.VCS interface.
+func (s *VCS) CanFetch(target string) bool {
+	if target == "" || len(s.proxies) == 0 || s.proxies[0].url == Direct {
+		return false
+	}
+	return !path.Match(s.noProxy, target)
+}
+
+// FetchPath implements the vcs.VCS interface.
+// Each proxy of the list is tried in turn, regarding the GOPROXY semantics.
+func (s *VCS) FetchPath(ctx context.Context, path string) (res semver.Tags, err error) {
+	err = s.walk(ctx, path, func(u string) error {
+		res, err = s.FetchURL(ctx, u)
+		return err
+	})
+	return
+}
+
+// FetchURL implements the vcs.VCS interface.
+// The URL must be the one of the module on the proxy, like https://proxy.golang.org/golang.org/x/mod.
+func (s *VCS) FetchURL(ctx context.Context, url string) (semver.Tags, error) {
+	if !s.ready(ctx) {
+		return nil, errs.ErrSystem
+	}
+	if url == "" {
+		return nil, errs.ErrRepository
+	}
+	res, err := s.list(ctx, url)
+	if err != nil || len(res) > 0 {
+		return res, err
+	}
+	// Without tagged version, the proxy can still resolve a pseudo-version.
+	inf, err := s.info(ctx, url+latestPath)
+	if err != nil {
+		return nil, err
+	}
+	return semver.Tags{semver.New(inf.Version)}, nil
+}
+
+// Info describes a module version.
+type Info struct {
+	Version string
+	Time    time.Time
+}
+
+// Info returns the metadata of this version of the module behind this path.
+func (s *VCS) Info(ctx context.Context, path, version string) (inf *Info, err error) {
+	v, err := module.EscapeVersion(version)
+	if err != nil {
+		return nil, vcs.Errorf(Name, errs.ErrRepository, err)
+	}
+	err = s.walk(ctx, path, func(u string) error {
+		inf, err = s.info(ctx, u+versionPath+v+infoExt)
+		return err
+	})
+	return
+}
+
+// Latest returns the metadata of the latest version of the module behind this path.
+func (s *VCS) Latest(ctx context.Context, path string) (inf *Info, err error) {
+	err = s.walk(ctx, path, func(u string) error {
+		inf, err = s.info(ctx, u+latestPath)
+		return err
+	})
+	return
+}
+
+// FetchMod implements the vcs.ModFetcher interface.
+func (s *VCS) FetchMod(ctx context.Context, path, version string) (res []byte, err error) {
+	err = s.walk(ctx, path, func(u string) error {
+		res, err = s.FetchModURL(ctx, u, "", version)
+		return err
+	})
+	return
+}
+
+// FetchModURL implements the vcs.ModFetcher interface.
+// The URL must be the one of the module on the proxy, the directory is ignored.
+func (s *VCS) FetchModURL(ctx context.Context, url, _, version string) ([]byte, error) {
+	if !s.ready(ctx) {
+		return nil, errs.ErrSystem
+	}
+	if url == "" {
+		return nil, errs.ErrRepository
+	}
+	v, err := module.EscapeVersion(version)
+	if err != nil {
+		return nil, vcs.Errorf(Name, errs.ErrRepository, err)
+	}
+	return s.read(ctx, url+versionPath+v+modExt)
+}
+
+// FetchZip implements the vcs.ZipFetcher interface.
+// The zip archive of the module version is downloaded from the first proxy serving it.
+func (s *VCS) FetchZip(ctx context.Context, path, version string) (res []byte, err error) {
+	v, err := module.EscapeVersion(version)
+	if err != nil {
+		return nil, vcs.Errorf(Name, errs.ErrRepository, err)
+	}
+	err = s.walk(ctx, path, func(u string) error {
+		res, err = s.read(ctx, u+versionPath+v+zipExt)
+		return err
+	})
+	return
+}
+
+const (
+	latestPath  = "/@latest"
+	listPath    = "/@v/list"
+	versionPath = "/@v/"
+	infoExt     = ".info"
+	modExt      = ".mod"
+	zipExt      = ".zip"
+	slash       = "/"
+)
+
+// walk calls fn with the URL of the module on each proxy of the list until it succeeds
+// or until the error does not allow to try the next one.
+func (s *VCS) walk(ctx context.Context, path string, fn func(moduleURL string) error) error {
+	if !s.ready(ctx) {
+		return errs.ErrSystem
+	}
+	if path == "" {
+		return errs.ErrRepository
+	}
+	p, err := module.EscapePath(path)
+	if err != nil {
+		return vcs.Errorf(Name, errs.ErrRepository, err)
+	}
+	err = errs.ErrSystem
+	for _, proxy := range s.proxies {
+		switch proxy.url {
+		case Direct:
+			return vcs.Errorf(Name, errs.ErrDirect)
+		case Off:
+			return vcs.Errorf(Name, errs.ErrSystem, "disabled by GOPROXY="+Off)
+		}
+		err = fn(proxy.url + slash + p)
+		if err == nil || !proxy.fallback(err) {
+			break
+		}
+	}
+	return err
+}
+
+func (s *VCS) list(ctx context.Context, url string) (semver.Tags, error) {
+	body, err := s.get(ctx, url+listPath)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = body.Close() }()
+
+	var (
+		res semver.Tags
+		buf = bufio.NewScanner(body)
+	)
+	for buf.Scan() {
+		// Each line may contain the version followed by extra fields, like a timestamp.
+		if f := strings.Fields(buf.Text()); len(f) > 0 {
+			res = append(res, semver.New(f[0]))
+		}
+	}
+	if err = buf.Err(); err != nil {
+		return nil, vcs.Errorf(Name, errs.ErrFetch, err)
+	}
+	return res, nil
+}
+
+func (s *VCS) info(ctx context.Context, url string) (*Info, error) {
+	body, err := s.get(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = body.Close() }()
+
+	inf := new(Info)
+	err = json.NewDecoder(body).Decode(inf)
+	if err != nil {
+		return nil, vcs.Errorf(Name, errs.ErrFetch, err)
+	}
+	return inf, nil
+}
+
+func (s *VCS) read(ctx context.Context, rawURL string) ([]byte, error) {
+	body, err := s.get(ctx, rawURL)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = body.Close() }()
+
+	res, err := io.ReadAll(body)
+	if err != nil {
+		return nil, vcs.Errorf(Name, errs.ErrFetch, err)
+	}
+	return res, nil
+}
+
+func (s *VCS) get(ctx context.Context, rawURL string) (io.ReadCloser, error) {
+	return vcs.Get(ctx, s.http, s.auth, Name, rawURL)
+}
+
+func (s *VCS) ready(ctx context.Context) bool {
+	return ctx != nil && s.http != nil
+}
+
+type proxy struct {
+	url string
+	// anyError is true when the proxy is followed by a pipe in the list:
+	// any error allows to try the next one.
+	// Otherwise, only the not found errors allow it.
+	anyError bool
+}
+
+func (p proxy) fallback(err error) bool {
+	return p.anyError || errors.Is(err, errs.ErrNotFound)
+}
+
+const (
+	separators = ",|"
+	pipe       = '|'
+)
+
+// parseList parses a GOPROXY value.
+// Once the keyword direct or off is found, the next proxies are ignored.
+func parseList(list string) []proxy {
+	var res []proxy
+	for list != "" {
+		var (
+			p proxy
+			i = strings.IndexAny(list, separators)
+		)
+		if i < 0 {
+			p.url, list = list, ""
+		} else {
+			p.url, p.anyError, list = list[:i], list[i] == pipe, list[i+1:]
+		}
+		if p.url = strings.TrimRight(strings.TrimSpace(p.url), slash); p.url == "" {
+			continue
+		}
+		res = append(res, p)
+		if p.url == Direct || p.url == Off {
+			break
+		}
+	}
+	return res
+}
@@ -0,0 +1,48 @@
+// Copyright (c) 2020 Hervé Gouchet. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package goproxy
+
+import (
+	"testing"
+
+	"github.com/matryer/is"
+)
+
+func TestParseList(t *testing.T) {
+	t.Parallel()
+	var (
+		are = is.New(t)
+		dt  = map[string]struct {
+			in  string
+			out []proxy
+		}{
+			"default": {},
+			"direct":  {in: Direct, out: []proxy{{url: Direct}}},
+			"default go": {
+				in:  "https://proxy.golang.org,direct",
+				out: []proxy{{url: "https://proxy.golang.org"}, {url: Direct}},
+			},
+			"pipe": {
+				in:  "https://athens.example.lan/|https://proxy.golang.org, off",
+				out: []proxy{{url: "https://athens.example.lan", anyError: true}, {url: "https://proxy.golang.org"}, {url: Off}},
+			},
+			"ignore after off": {
+				in:  "off,https://proxy.golang.org",
+				out: []proxy{{url: Off}},
+			},
+			"empty parts": {
+				in:  ",https://proxy.golang.org,,",
+				out: []proxy{{url: "https://proxy.golang.org"}},
+			},
+		}
+	)
+	for name, ts := range dt {
+		tt := ts
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			are.Equal(parseList(tt.in), tt.out) // mismatch result
+		})
+	}
+}
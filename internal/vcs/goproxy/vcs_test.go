@@ -0,0 +1,194 @@
+// Copyright (c) 2020 Hervé Gouchet. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package goproxy_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/matryer/is"
+	errup "github.com/rvflash/goup/internal/errors"
+	"github.com/rvflash/goup/internal/semver"
+	"github.com/rvflash/goup/internal/vcs"
+	"github.com/rvflash/goup/internal/vcs/goproxy"
+)
+
+const (
+	pkgName    = "github.com/rvflash/goup"
+	pseudoName = "github.com/rvflash/pseudo"
+	pseudo     = "v0.0.0-20200121190230-accd165b1659"
+)
+
+func TestVCS_CanFetch(t *testing.T) {
+	t.Parallel()
+	var (
+		are = is.New(t)
+		dt  = map[string]struct {
+			list,
+			noProxy,
+			path string
+			out bool
+		}{
+			"default":  {},
+			"no path":  {list: "https://proxy.golang.org"},
+			"no proxy": {path: pkgName},
+			"direct":   {list: "direct,https://proxy.golang.org", path: pkgName},
+			"private":  {list: "https://proxy.golang.org", noProxy: "github.com/rvflash", path: pkgName},
+			"off":      {list: "off", path: pkgName, out: true},
+			"ok":       {list: "https://proxy.golang.org,direct", noProxy: "example.com", path: pkgName, out: true},
+		}
+	)
+	for name, ts := range dt {
+		tt := ts
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			s := goproxy.New(newHTTPClient(), nil, tt.list, tt.noProxy)
+			are.Equal(s.CanFetch(tt.path), tt.out) // mismatch result
+		})
+	}
+}
+
+func TestVCS_FetchPath(t *testing.T) {
+	t.Parallel()
+	var (
+		are = is.New(t)
+		ok  = newServer(t)
+		ko  = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+	)
+	defer ok.Close()
+	defer ko.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	var (
+		dt = map[string]struct {
+			ctx  context.Context
+			list string
+			path string
+			res  semver.Tags
+			err  error
+		}{
+			"default":      {err: errup.ErrSystem},
+			"missing path": {ctx: ctx, list: ok.URL, err: errup.ErrRepository},
+			"off":          {ctx: ctx, list: goproxy.Off, path: pkgName, err: errup.ErrSystem},
+			"not found":    {ctx: ctx, list: ok.URL, path: "example.com/pkg", err: errup.ErrNotFound},
+			"direct":       {ctx: ctx, list: ok.URL + ",direct", path: "example.com/pkg", err: errup.ErrDirect},
+			"failure":      {ctx: ctx, list: ko.URL + "," + ok.URL, path: pkgName, err: errup.ErrFetch},
+			"fallback": {
+				ctx:  ctx,
+				list: ko.URL + "|" + ok.URL,
+				path: pkgName,
+				res:  semver.Tags{semver.New("v0.1.0"), semver.New("v0.2.0")},
+			},
+			"ok": {
+				ctx:  ctx,
+				list: ok.URL,
+				path: pkgName,
+				res:  semver.Tags{semver.New("v0.1.0"), semver.New("v0.2.0")},
+			},
+			"latest": {
+				ctx:  ctx,
+				list: ok.URL,
+				path: pseudoName,
+				res:  semver.Tags{semver.New(pseudo)},
+			},
+		}
+	)
+	for name, ts := range dt {
+		tt := ts
+		t.Run(name, func(_ *testing.T) {
+			s := goproxy.New(newHTTPClient(), nil, tt.list, "")
+			res, err := s.FetchPath(tt.ctx, tt.path)
+			are.True(errors.Is(err, tt.err)) // mismatch error
+			are.Equal(res, tt.res)           // mismatch result
+		})
+	}
+}
+
+func TestVCS_Info(t *testing.T) {
+	t.Parallel()
+	var (
+		are = is.New(t)
+		srv = newServer(t)
+	)
+	defer srv.Close()
+
+	s := goproxy.New(newHTTPClient(), nil, srv.URL, "")
+	inf, err := s.Info(context.Background(), pseudoName, pseudo)
+	are.NoErr(err)                 // unexpected error
+	are.Equal(inf.Version, pseudo) // mismatch version
+	inf, err = s.Latest(context.Background(), pseudoName)
+	are.NoErr(err)                                                                 // unexpected error
+	are.Equal(inf.Time, time.Date(2020, time.January, 21, 19, 2, 30, 0, time.UTC)) // mismatch time
+}
+
+func TestVCS_FetchMod(t *testing.T) {
+	t.Parallel()
+	var (
+		are = is.New(t)
+		srv = newServer(t)
+	)
+	defer srv.Close()
+
+	s := goproxy.New(newHTTPClient(), nil, srv.URL, "")
+	res, err := s.FetchMod(context.Background(), pkgName, "v0.2.0")
+	are.NoErr(err)                                 // unexpected error
+	are.Equal(string(res), "module "+pkgName+"\n") // mismatch content
+	_, err = s.FetchMod(context.Background(), pkgName, "v0.3.0")
+	are.True(errors.Is(err, errup.ErrNotFound)) // mismatch error
+	_, err = s.FetchModURL(context.Background(), srv.URL+"/"+pkgName, "", "")
+	are.True(errors.Is(err, errup.ErrRepository)) // mismatch error
+}
+
+func TestVCS_FetchZip(t *testing.T) {
+	t.Parallel()
+	var (
+		are = is.New(t)
+		srv = newServer(t)
+	)
+	defer srv.Close()
+
+	s := goproxy.New(newHTTPClient(), nil, srv.URL, "")
+	res, err := s.FetchZip(context.Background(), pkgName, "v0.2.0")
+	are.NoErr(err)                // unexpected error
+	are.Equal(string(res), "zip") // mismatch content
+	_, err = s.FetchZip(context.Background(), pkgName, "v0.3.0")
+	are.True(errors.Is(err, errup.ErrNotFound)) // mismatch error
+	_, err = goproxy.New(newHTTPClient(), nil, goproxy.Direct, "").FetchZip(context.Background(), pkgName, "v0.2.0")
+	are.True(errors.Is(err, errup.ErrDirect)) // mismatch error
+}
+
+func newServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	const info = `{"Version":"` + pseudo + `","Time":"2020-01-21T19:02:30Z"}`
+	mux := http.NewServeMux()
+	mux.HandleFunc("/"+pkgName+"/@v/list", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte("v0.1.0\nv0.2.0 2020-01-21T19:02:30Z\n"))
+	})
+	mux.HandleFunc("/"+pkgName+"/@v/v0.2.0.mod", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte("module " + pkgName + "\n"))
+	})
+	mux.HandleFunc("/"+pkgName+"/@v/v0.2.0.zip", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte("zip"))
+	})
+	mux.HandleFunc("/"+pseudoName+"/@v/list", func(http.ResponseWriter, *http.Request) {})
+	mux.HandleFunc("/"+pseudoName+"/@latest", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(info))
+	})
+	mux.HandleFunc("/"+pseudoName+"/@v/"+pseudo+".info", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(info))
+	})
+	return httptest.NewServer(mux)
+}
+
+func newHTTPClient() *vcs.HTTPClient {
+	return vcs.NewHTTPClient(time.Second, "")
+}
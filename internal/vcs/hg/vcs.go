@@ -0,0 +1,110 @@
+// Copyright (c) 2020 Hervé Gouchet. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+// Package hg provides methods to list the tags of a Mercurial repository served over HTTP by hgweb.
+package hg
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net/url"
+	"strings"
+
+	"github.com/rvflash/goup/internal/errors"
+	"github.com/rvflash/goup/internal/semver"
+	"github.com/rvflash/goup/internal/vcs"
+)
+
+// Name is the name of this VCS.
+const Name = "hg"
+
+// tagsPath is the path of the file listing the tags in the last revision.
+const tagsPath = "/raw-file/tip/.hgtags"
+
+// VCS is a Mercurial version control system.
+type VCS struct {
+	auth vcs.BasicAuthentifier
+	http vcs.ClientChooser
+}
+
+// New returns a new instance of VCS.
+func New(client vcs.ClientChooser, auth vcs.BasicAuthentifier) *VCS {
+	return &VCS{
+		auth: auth,
+		http: client,
+	}
+}
+
+// CanFetch implements the vcs.VCS interface.
+// The VCS of a repository can not be guessed with its path, so it's only used behind go-import metadata.
+func (s *VCS) CanFetch(_ string) bool {
+	return false
+}
+
+// FetchPath implements the vcs.VCS interface.
+func (s *VCS) FetchPath(ctx context.Context, path string) (semver.Tags, error) {
+	if path == "" {
+		return nil, errors.ErrRepository
+	}
+	return s.FetchURL(ctx, vcs.URLScheme(vcs.HTTPS)+path)
+}
+
+// FetchURL implements the vcs.VCS interface.
+func (s *VCS) FetchURL(ctx context.Context, rawURL string) (semver.Tags, error) {
+	if ctx == nil || s.http == nil {
+		return nil, errors.ErrSystem
+	}
+	u, err := url.Parse(strings.TrimSuffix(rawURL, "/") + tagsPath)
+	if err != nil || rawURL == "" {
+		return nil, vcs.Errorf(Name, errors.ErrRepository)
+	}
+	// Security check
+	if !vcs.IsSecureScheme(u.Scheme) && !s.http.AllowInsecure(vcs.RepoPath(u)) {
+		return nil, vcs.Errorf(Name, errors.ErrRepository, errors.NewSecurityIssue(u.String()))
+	}
+	body, err := vcs.Get(ctx, s.http, s.auth, Name, u.String())
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = body.Close() }()
+
+	return parseTags(body)
+}
+
+// nullNode is used by Mercurial to mark a tag as removed.
+const nullNode = "0000000000000000000000000000000000000000"
+
+// parseTags parses the .hgtags file: each line contains a node and a tag name.
+// The last line of a tag wins.
+func parseTags(r io.Reader) (semver.Tags, error) {
+	var (
+		names []string
+		nodes = make(map[string]string)
+		sc    = bufio.NewScanner(r)
+	)
+	for sc.Scan() {
+		f := strings.Fields(sc.Text())
+		if len(f) != 2 {
+			continue
+		}
+		if _, ok := nodes[f[1]]; !ok {
+			names = append(names, f[1])
+		}
+		nodes[f[1]] = f[0]
+	}
+	if err := sc.Err(); err != nil {
+		return nil, vcs.Errorf(Name, errors.ErrFetch, err)
+	}
+	var res semver.Tags
+	for _, name := range names {
+		if nodes[name] == nullNode {
+			continue
+		}
+		if v := semver.New(name); v.IsValid() {
+			res = append(res, v)
+		}
+	}
+	return res, nil
+}
@@ -0,0 +1,77 @@
+// Copyright (c) 2020 Hervé Gouchet. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package hg_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/matryer/is"
+	errup "github.com/rvflash/goup/internal/errors"
+	"github.com/rvflash/goup/internal/vcs"
+	"github.com/rvflash/goup/internal/vcs/hg"
+)
+
+const hgTags = `0c7b3e1a9b2c4d5e6f708192a3b4c5d6e7f80912 v0.1.0
+1d8c4f2b0c3d5e6f708192a3b4c5d6e7f8091223 v0.2.0
+2e9d503c1d4e6f708192a3b4c5d6e7f809122334 sub/v0.1.0
+3fae614d2e5f708192a3b4c5d6e7f80912233445 nightly
+1d8c4f2b0c3d5e6f708192a3b4c5d6e7f8091223 v0.2.0
+1d8c4f2b0c3d5e6f708192a3b4c5d6e7f8091223 v0.3.0
+0000000000000000000000000000000000000000 v0.3.0
+`
+
+func TestVCS_CanFetch(t *testing.T) {
+	t.Parallel()
+	is.New(t).True(!hg.New(nil, nil).CanFetch("example.com/repo")) // unexpected fetch
+}
+
+func TestVCS_FetchURL(t *testing.T) {
+	t.Parallel()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repo/raw-file/tip/.hgtags" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(hgTags))
+	}))
+	defer srv.Close()
+	var (
+		are = is.New(t)
+		dt  = map[string]struct {
+			cli vcs.ClientChooser
+			ctx context.Context
+			url string
+			out []string
+			err error
+		}{
+			"default":   {err: errup.ErrSystem},
+			"no url":    {cli: vcs.NewHTTPClient(time.Second, "127.0.0.1:*"), ctx: context.Background(), err: errup.ErrRepository},
+			"insecure":  {cli: vcs.NewHTTPClient(time.Second, ""), ctx: context.Background(), url: srv.URL + "/repo", err: errup.ErrRepository},
+			"not found": {cli: vcs.NewHTTPClient(time.Second, "127.0.0.1:*"), ctx: context.Background(), url: srv.URL + "/oops", err: errup.ErrNotFound},
+			"ok": {
+				cli: vcs.NewHTTPClient(time.Second, "127.0.0.1:*"),
+				ctx: context.Background(),
+				url: srv.URL + "/repo/",
+				out: []string{"v0.1.0", "v0.2.0", "sub/v0.1.0"},
+			},
+		}
+	)
+	for name, ts := range dt {
+		tt := ts
+		t.Run(name, func(_ *testing.T) {
+			res, err := hg.New(tt.cli, nil).FetchURL(tt.ctx, tt.url)
+			are.True(errors.Is(err, tt.err)) // mismatch error
+			are.Equal(len(res), len(tt.out)) // mismatch length
+			for k, v := range res {
+				are.Equal(v.String(), tt.out[k]) // mismatch tag
+			}
+		})
+	}
+}
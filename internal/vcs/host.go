@@ -0,0 +1,99 @@
+// Copyright (c) 2020 Hervé Gouchet. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package vcs
+
+import (
+	"strings"
+
+	"github.com/rvflash/goup/internal/path"
+
+	"golang.org/x/mod/module"
+)
+
+// DefaultHosts is the comma-separated list of glob patterns matching the repository root of well-known hosts.
+const DefaultHosts = "github.com/*/*,bitbucket.org/*/*,dev.azure.com/*/*/_git/*,*/scm/*/*"
+
+// Hosts is a registry describing the layout of repository paths by host.
+// Each glob pattern matches the root path of a repository, like gitlab.example.lan/*/*/* for subgroups.
+type Hosts string
+
+// NewHosts returns a new registry with the given comma-separated glob patterns.
+// They are tested before the ones of the well-known hosts.
+func NewHosts(patterns string) Hosts {
+	if patterns = strings.Trim(patterns, " ,"); patterns == "" {
+		return DefaultHosts
+	}
+	return Hosts(patterns + comma + DefaultHosts)
+}
+
+// Root returns the root path of the repository if the host layout is known.
+// The gopkg.in paths are served by their upstream GitHub repository.
+func (h Hosts) Root(modulePath string) (string, bool) {
+	if root, _, ok := Gopkg(modulePath); ok {
+		return root, true
+	}
+	return path.Prefix(string(h), modulePath)
+}
+
+// GopkgIn is the host of the gopkg.in service, serving a major version of a GitHub repository
+// under a path suffixed by .vN, like gopkg.in/yaml.v2.
+const GopkgIn = "gopkg.in"
+
+const (
+	gopkgOwner    = "go-"
+	gopkgUnstable = "-unstable"
+	github        = "github.com"
+)
+
+// Gopkg returns the root path of the GitHub repository behind this gopkg.in module path,
+// and the major version it serves: gopkg.in/pkg.v3 is github.com/go-pkg/pkg at v3
+// and gopkg.in/user/pkg.v3, github.com/user/pkg.
+func Gopkg(modulePath string) (root, major string, ok bool) {
+	if !strings.HasPrefix(modulePath, GopkgIn+slash) {
+		return "", "", false
+	}
+	prefix, pathMajor, ok := module.SplitPathVersion(modulePath)
+	if !ok || !strings.HasPrefix(pathMajor, ".v") {
+		return "", "", false
+	}
+	major = strings.TrimSuffix(pathMajor[1:], gopkgUnstable)
+	p := strings.Split(strings.TrimPrefix(prefix, GopkgIn+slash), slash)
+	switch len(p) {
+	case 1:
+		return github + slash + gopkgOwner + p[0] + slash + p[0], major, true
+	case 2:
+		return github + slash + p[0] + slash + p[1], major, true
+	default:
+		return "", "", false
+	}
+}
+
+const (
+	comma = ","
+	slash = "/"
+	// The shortest repository root has a host and a name, like go.uber.org/zap.
+	minNumPart = 2
+)
+
+// Roots returns the candidates to be the root path of the repository behind this module path.
+// With a known host layout, the root is known. Otherwise, any prefix of the path can be the root,
+// so they are listed from the longest to the shortest.
+func (h Hosts) Roots(modulePath string) []string {
+	if modulePath == "" {
+		return nil
+	}
+	if root, ok := h.Root(modulePath); ok {
+		return []string{root}
+	}
+	p := strings.Split(strings.Trim(modulePath, slash), slash)
+	if len(p) < minNumPart {
+		return []string{modulePath}
+	}
+	res := make([]string, 0, len(p)-minNumPart+1)
+	for i := len(p); i >= minNumPart; i-- {
+		res = append(res, strings.Join(p[:i], slash))
+	}
+	return res
+}
@@ -0,0 +1,86 @@
+// Copyright (c) 2020 Hervé Gouchet. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package vcs_test
+
+import (
+	"testing"
+
+	"github.com/matryer/is"
+	"github.com/rvflash/goup/internal/vcs"
+)
+
+func TestHosts_Roots(t *testing.T) {
+	t.Parallel()
+	var (
+		are = is.New(t)
+		dt  = map[string]struct {
+			hosts string
+			in    string
+			out   []string
+		}{
+			"default": {},
+			"github":  {in: "github.com/rvflash/goup/pkg/mod", out: []string{"github.com/rvflash/goup"}},
+			"azure": {
+				in:  "dev.azure.com/org/project/_git/repo/sub",
+				out: []string{"dev.azure.com/org/project/_git/repo"},
+			},
+			"bitbucket server": {
+				in:  "git.example.lan/scm/project/repo",
+				out: []string{"git.example.lan/scm/project/repo"},
+			},
+			"gitlab subgroup": {
+				hosts: "gitlab.example.lan/*/*/*",
+				in:    "gitlab.example.lan/group/sub/project/v2",
+				out:   []string{"gitlab.example.lan/group/sub/project"},
+			},
+			"unknown": {
+				in:  "gitlab.example.lan/group/sub/project",
+				out: []string{"gitlab.example.lan/group/sub/project", "gitlab.example.lan/group/sub", "gitlab.example.lan/group"},
+			},
+			"host only": {in: "example.lan", out: []string{"example.lan"}},
+			"gopkg.in":  {in: "gopkg.in/yaml.v3", out: []string{"github.com/go-yaml/yaml"}},
+		}
+	)
+	for name, ts := range dt {
+		tt := ts
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			are.Equal(vcs.NewHosts(tt.hosts).Roots(tt.in), tt.out) // mismatch result
+		})
+	}
+}
+
+func TestGopkg(t *testing.T) {
+	t.Parallel()
+	var (
+		are = is.New(t)
+		dt  = map[string]struct {
+			in    string
+			root  string
+			major string
+			ok    bool
+		}{
+			"default":     {},
+			"not gopkg":   {in: "github.com/go-yaml/yaml/v3"},
+			"no major":    {in: "gopkg.in/yaml"},
+			"bad major":   {in: "gopkg.in/yaml/v3"},
+			"too deep":    {in: "gopkg.in/a/b/c.v1"},
+			"package":     {in: "gopkg.in/yaml.v3", root: "github.com/go-yaml/yaml", major: "v3", ok: true},
+			"user":        {in: "gopkg.in/src-d/go-git.v4", root: "github.com/src-d/go-git", major: "v4", ok: true},
+			"unstable":    {in: "gopkg.in/check.v1-unstable", root: "github.com/go-check/check", major: "v1", ok: true},
+			"v0 accepted": {in: "gopkg.in/pkg.v0", root: "github.com/go-pkg/pkg", major: "v0", ok: true},
+		}
+	)
+	for name, ts := range dt {
+		tt := ts
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			root, major, ok := vcs.Gopkg(tt.in)
+			are.Equal(ok, tt.ok)       // mismatch ok
+			are.Equal(root, tt.root)   // mismatch root
+			are.Equal(major, tt.major) // mismatch major
+		})
+	}
+}
@@ -5,23 +5,49 @@
 package vcs
 
 import (
+	"context"
 	"crypto/tls"
 	"fmt"
+	"io"
 	"net/http"
 	"time"
 
+	"github.com/rvflash/goup/internal/errors"
 	"github.com/rvflash/goup/internal/path"
 )
 
 const https = "https"
 
+// ClientOption allows to customize the HTTPClient.
+type ClientOption func(c *HTTPClient)
+
+// WithLimiter limits the requests sent by the HTTPClient.
+func WithLimiter(l *Limiter) ClientOption {
+	return func(c *HTTPClient) {
+		if l == nil {
+			return
+		}
+		for _, hc := range []*http.Client{c.secure, c.insecure} {
+			next := hc.Transport
+			if next == nil {
+				next = http.DefaultTransport
+			}
+			hc.Transport = &limitedTransport{next: next, limiter: l}
+		}
+	}
+}
+
 // NewHTTPClient creates a new instance of Client.
-func NewHTTPClient(timeout time.Duration, insecurePaths string) *HTTPClient {
-	return &HTTPClient{
+func NewHTTPClient(timeout time.Duration, insecurePaths string, opts ...ClientOption) *HTTPClient {
+	c := &HTTPClient{
 		insecure:      newInsecureHTTPClient(timeout),
 		secure:        newSecureHTTPClient(timeout),
 		insecurePaths: insecurePaths,
 	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
 }
 
 // HTTPClient allows to communicate over HTTPClient or HTTPS.
@@ -70,3 +96,43 @@ func newSecureHTTPClient(timeout time.Duration) *http.Client {
 		},
 	}
 }
+
+// Get requests this URL with the basic authentication of its host, if any, and returns the body of the response.
+// The not found and gone responses return errors.ErrNotFound, other failures errors.ErrFetch.
+// The network failures, the too many requests and the server errors are marked as temporary.
+// The name of the VCS prefixes the errors.
+func Get(ctx context.Context, client ClientChooser, auth BasicAuthentifier, name, rawURL string) (io.ReadCloser, error) {
+	if ctx == nil || client == nil {
+		return nil, errors.ErrSystem
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, Errorf(name, errors.ErrRepository, err)
+	}
+	if auth != nil {
+		if ba := auth.BasicAuth(req.URL.Host); ba != nil {
+			req.SetBasicAuth(ba.Username, ba.Password)
+		}
+	}
+	resp, err := client.ClientFor(RepoPath(req.URL)).Do(req)
+	if err != nil {
+		if IsTemporary(err) {
+			return nil, Temporary(Errorf(name, errors.ErrFetch, err))
+		}
+		return nil, Errorf(name, errors.ErrFetch, err)
+	}
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return resp.Body, nil
+	case http.StatusNotFound, http.StatusGone:
+		_ = resp.Body.Close()
+		return nil, Errorf(name, errors.ErrNotFound, req.URL.String())
+	default:
+		_ = resp.Body.Close()
+		err = Errorf(name, errors.ErrFetch, fmt.Sprintf("%s: %s", req.URL, resp.Status))
+		if IsTemporaryStatus(resp.StatusCode) {
+			return nil, Temporary(err)
+		}
+		return nil, err
+	}
+}
@@ -0,0 +1,186 @@
+// Copyright (c) 2020 Hervé Gouchet. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package vcs
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// NewLimiter returns a new instance of Limiter.
+// At most maxRequests requests are in progress at the same time and each host is requested at most rate times
+// per second. With zero or less, the related limit is disabled.
+func NewLimiter(maxRequests int, rate float64) *Limiter {
+	l := &Limiter{
+		rate:  rate,
+		burst: math.Max(1, math.Floor(rate)),
+		hosts: make(map[string]*bucket),
+	}
+	if maxRequests > 0 {
+		l.sem = make(chan struct{}, maxRequests)
+	}
+	return l
+}
+
+// Limiter bounds the number of requests in progress and applies a token bucket by host.
+// A nil Limiter does not limit anything.
+type Limiter struct {
+	sem   chan struct{}
+	rate  float64
+	burst float64
+
+	mu    sync.Mutex
+	hosts map[string]*bucket
+}
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+	until  time.Time
+}
+
+// Wait blocks until a request to this host is allowed or the context is done.
+// Once the request completed, the returned function must be called to release its slot.
+func (l *Limiter) Wait(ctx context.Context, host string) (release func(), err error) {
+	if l == nil {
+		return func() {}, nil
+	}
+	for {
+		d := l.reserve(host)
+		if d <= 0 {
+			break
+		}
+		if err = sleep(ctx, d); err != nil {
+			return nil, err
+		}
+	}
+	if l.sem == nil {
+		return func() {}, nil
+	}
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case l.sem <- struct{}{}:
+		var once sync.Once
+		return func() {
+			once.Do(func() { <-l.sem })
+		}, nil
+	}
+}
+
+// Delay pauses the requests to this host during this duration, as asked by a Retry-After header.
+func (l *Limiter) Delay(host string, d time.Duration) {
+	if l == nil || d <= 0 {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	b := l.bucket(host, time.Now())
+	if until := time.Now().Add(d); until.After(b.until) {
+		b.until = until
+	}
+}
+
+// reserve takes a token of the host and returns zero or the duration to wait before retrying.
+func (l *Limiter) reserve(host string) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := time.Now()
+	b := l.bucket(host, now)
+	if now.Before(b.until) {
+		return b.until.Sub(now)
+	}
+	if l.rate <= 0 {
+		return 0
+	}
+	b.tokens = math.Min(l.burst, b.tokens+now.Sub(b.last).Seconds()*l.rate)
+	b.last = now
+	if b.tokens >= 1 {
+		b.tokens--
+		return 0
+	}
+	return time.Duration((1 - b.tokens) / l.rate * float64(time.Second))
+}
+
+func (l *Limiter) bucket(host string, now time.Time) *bucket {
+	b, ok := l.hosts[host]
+	if !ok {
+		b = &bucket{tokens: l.burst, last: now}
+		l.hosts[host] = b
+	}
+	return b
+}
+
+type limitedTransport struct {
+	next    http.RoundTripper
+	limiter *Limiter
+}
+
+// RoundTrip implements the http.RoundTripper interface.
+// It waits for the limiter before sending the request and respects the Retry-After header
+// of the too many requests or unavailable responses: the following requests to the host are delayed
+// and a request without body is sent again once.
+func (t *limitedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		release, err := t.limiter.Wait(req.Context(), req.URL.Host)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := t.next.RoundTrip(req)
+		release()
+		if err != nil {
+			return nil, err
+		}
+		d, ok := RetryAfter(resp)
+		if !ok {
+			return resp, nil
+		}
+		t.limiter.Delay(req.URL.Host, d)
+		if attempt > 0 || req.Body != nil {
+			return resp, nil
+		}
+		_ = resp.Body.Close()
+	}
+}
+
+// RetryAfter returns the delay asked by the Retry-After header of the too many requests
+// or unavailable response. It returns false if there is no such delay.
+func RetryAfter(resp *http.Response) (time.Duration, bool) {
+	if resp == nil {
+		return 0, false
+	}
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusServiceUnavailable:
+	default:
+		return 0, false
+	}
+	v := resp.Header.Get("Retry-After")
+	if v == "" {
+		return 0, false
+	}
+	if s, err := strconv.Atoi(v); err == nil {
+		return time.Duration(s) * time.Second, s >= 0
+	}
+	t, err := http.ParseTime(v)
+	if err != nil {
+		return 0, false
+	}
+	return time.Until(t), true
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
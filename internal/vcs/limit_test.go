@@ -0,0 +1,160 @@
+// Copyright (c) 2020 Hervé Gouchet. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package vcs_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/matryer/is"
+	"github.com/rvflash/goup/internal/vcs"
+)
+
+const host = "example.com"
+
+func TestLimiter_Wait(t *testing.T) {
+	t.Parallel()
+	t.Run("nil", func(t *testing.T) {
+		are := is.New(t)
+		var l *vcs.Limiter
+		release, err := l.Wait(context.Background(), host)
+		are.NoErr(err) // unexpected error
+		release()
+	})
+	t.Run("max requests", func(t *testing.T) {
+		var (
+			are         = is.New(t)
+			l           = vcs.NewLimiter(2, 0)
+			ctx, cancel = context.WithTimeout(context.Background(), 10*time.Millisecond)
+		)
+		defer cancel()
+		r1, err := l.Wait(ctx, host)
+		are.NoErr(err) // unexpected error
+		_, err = l.Wait(ctx, "other.com")
+		are.NoErr(err) // unexpected error
+		_, err = l.Wait(ctx, host)
+		are.Equal(err, context.DeadlineExceeded) // expected limit
+		r1()
+		r1()
+		_, err = l.Wait(context.Background(), host)
+		are.NoErr(err) // unexpected error after release
+	})
+	t.Run("rate", func(t *testing.T) {
+		var (
+			are   = is.New(t)
+			l     = vcs.NewLimiter(0, 20)
+			start = time.Now()
+		)
+		// The burst allows the first 20 requests, the next ones wait for a token.
+		for i := 0; i < 22; i++ {
+			_, err := l.Wait(context.Background(), host)
+			are.NoErr(err) // unexpected error
+		}
+		are.True(time.Since(start) >= 90*time.Millisecond) // expected rate limit
+		start = time.Now()
+		_, err := l.Wait(context.Background(), "other.com")
+		are.NoErr(err)                                    // unexpected error
+		are.True(time.Since(start) < 50*time.Millisecond) // the other hosts are not limited
+	})
+	t.Run("delay", func(t *testing.T) {
+		var (
+			are         = is.New(t)
+			l           = vcs.NewLimiter(0, 0)
+			ctx, cancel = context.WithTimeout(context.Background(), 10*time.Millisecond)
+		)
+		defer cancel()
+		l.Delay(host, time.Minute)
+		_, err := l.Wait(ctx, host)
+		are.Equal(err, context.DeadlineExceeded) // expected delay
+	})
+}
+
+func TestRetryAfter(t *testing.T) {
+	t.Parallel()
+	var (
+		are = is.New(t)
+		dt  = map[string]struct {
+			code  int
+			value string
+			out   time.Duration
+			ok    bool
+		}{
+			"default":           {code: http.StatusOK, value: "1"},
+			"too many":          {code: http.StatusTooManyRequests, value: "2", out: 2 * time.Second, ok: true},
+			"unavailable":       {code: http.StatusServiceUnavailable, value: "0", ok: true},
+			"without header":    {code: http.StatusTooManyRequests},
+			"invalid":           {code: http.StatusTooManyRequests, value: "soon"},
+			"negative":          {code: http.StatusTooManyRequests, value: "-1", out: -time.Second},
+			"http date in past": {code: http.StatusTooManyRequests, value: "Mon, 02 Jan 2006 15:04:05 GMT", ok: true},
+		}
+	)
+	for name, ts := range dt {
+		tt := ts
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			resp := &http.Response{StatusCode: tt.code, Header: http.Header{}}
+			if tt.value != "" {
+				resp.Header.Set("Retry-After", tt.value)
+			}
+			d, ok := vcs.RetryAfter(resp)
+			are.Equal(ok, tt.ok) // mismatch result
+			if tt.out != 0 {
+				are.Equal(d, tt.out) // mismatch delay
+			}
+		})
+	}
+}
+
+func TestWithLimiter(t *testing.T) {
+	t.Parallel()
+	var (
+		are   = is.New(t)
+		calls int32
+		ts    = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			if atomic.AddInt32(&calls, 1) == 1 {
+				w.Header().Set("Retry-After", "0")
+				w.WriteHeader(http.StatusTooManyRequests)
+				return
+			}
+			_, _ = io.WriteString(w, "ok")
+		}))
+		c  = vcs.NewHTTPClient(time.Second, "127.0.0.1*", vcs.WithLimiter(vcs.NewLimiter(1, 0)))
+		wg sync.WaitGroup
+	)
+	defer ts.Close()
+	// The first response asks to retry, the request is sent again.
+	resp, err := c.ClientFor("127.0.0.1").Do(mustRequest(t, ts.URL))
+	are.NoErr(err)                                // unexpected error
+	are.Equal(resp.StatusCode, http.StatusOK)     // mismatch status
+	are.Equal(atomic.LoadInt32(&calls), int32(2)) // mismatch calls
+	_ = resp.Body.Close()
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp, err := c.ClientFor("127.0.0.1").Do(mustRequest(t, ts.URL))
+			if err == nil {
+				_ = resp.Body.Close()
+			}
+		}()
+	}
+	wg.Wait()
+	are.Equal(atomic.LoadInt32(&calls), int32(5)) // mismatch calls
+}
+
+func mustRequest(t *testing.T, rawURL string) *http.Request {
+	t.Helper()
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, rawURL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return req
+}
@@ -0,0 +1,120 @@
+// Copyright (c) 2020 Hervé Gouchet. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package vcs
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"sync/atomic"
+	"syscall"
+	"time"
+)
+
+// Backoff is the policy used to retry the requests failed with a temporary error.
+// The delay between two attempts grows exponentially from Base to Max, with a random jitter.
+// With less than two attempts, the requests are not retried.
+type Backoff struct {
+	Attempts int
+	Base     time.Duration
+	Max      time.Duration
+}
+
+// DefaultBackoff is the default retry policy.
+var DefaultBackoff = Backoff{Attempts: 3, Base: 250 * time.Millisecond, Max: 5 * time.Second}
+
+// Retry calls fn until it succeeds, fails with a not temporary error, the attempts are exhausted
+// or the context is done. Each new attempt is counted in the context, see WithRetries.
+func (b Backoff) Retry(ctx context.Context, fn func() error) error {
+	if ctx == nil {
+		return fn()
+	}
+	for attempt := 1; ; attempt++ {
+		if c, ok := ctx.Value(retriesKey{}).(*int32); ok && attempt > 1 {
+			atomic.AddInt32(c, 1)
+		}
+		err := fn()
+		if err == nil || attempt >= b.Attempts || !IsTemporary(err) || ctx.Err() != nil {
+			return err
+		}
+		if sleep(ctx, b.delay(attempt)) != nil {
+			return err
+		}
+	}
+}
+
+// delay returns the duration to wait after this attempt, between the half and the whole exponential delay.
+func (b Backoff) delay(attempt int) time.Duration {
+	d := b.Base << (attempt - 1)
+	if d <= 0 || (b.Max > 0 && d > b.Max) {
+		d = b.Max
+	}
+	if d <= 0 {
+		return 0
+	}
+	return d/2 + time.Duration(rand.Int63n(int64(d/2)+1))
+}
+
+type retriesKey struct{}
+
+// WithRetries returns a copy of the context counting the attempts retried by the requests sent with it.
+func WithRetries(ctx context.Context) context.Context {
+	return context.WithValue(ctx, retriesKey{}, new(int32))
+}
+
+// Retries returns the number of attempts retried in this context, see WithRetries.
+func Retries(ctx context.Context) int {
+	if c, ok := ctx.Value(retriesKey{}).(*int32); ok {
+		return int(atomic.LoadInt32(c))
+	}
+	return 0
+}
+
+// Temporary marks the error as temporary, so worth a retry.
+func Temporary(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &temporaryError{err: err}
+}
+
+type temporaryError struct {
+	err error
+}
+
+// Error implements the error interface.
+func (e *temporaryError) Error() string {
+	return e.err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (e *temporaryError) Unwrap() error {
+	return e.err
+}
+
+// IsTemporary returns true if the error is marked as temporary or is a network failure,
+// like a connection reset or a timeout. The context errors are never temporary.
+func IsTemporary(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var te *temporaryError
+	if errors.As(err, &te) {
+		return true
+	}
+	if errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.EPIPE) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return true
+	}
+	var ne net.Error
+	return errors.As(err, &ne) && ne.Timeout()
+}
+
+// IsTemporaryStatus returns true if the HTTP status code is too many requests or a server error.
+func IsTemporaryStatus(code int) bool {
+	return code == http.StatusTooManyRequests || code >= http.StatusInternalServerError
+}
@@ -0,0 +1,133 @@
+// Copyright (c) 2020 Hervé Gouchet. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package vcs_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/matryer/is"
+	errup "github.com/rvflash/goup/internal/errors"
+	"github.com/rvflash/goup/internal/vcs"
+)
+
+func TestBackoff_Retry(t *testing.T) {
+	t.Parallel()
+	var (
+		are = is.New(t)
+		b   = vcs.Backoff{Attempts: 3, Base: time.Millisecond, Max: 2 * time.Millisecond}
+		dt  = map[string]struct {
+			backoff vcs.Backoff
+			errs    []error
+			calls   int
+			retries int
+			err     error
+		}{
+			"default":       {backoff: b, calls: 1},
+			"no retry":      {errs: []error{vcs.Temporary(errup.ErrFetch)}, calls: 1, err: errup.ErrFetch},
+			"not temporary": {backoff: b, errs: []error{errup.ErrFetch}, calls: 1, err: errup.ErrFetch},
+			"retried": {
+				backoff: b,
+				errs:    []error{vcs.Temporary(errup.ErrFetch), syscall.ECONNRESET},
+				calls:   3,
+				retries: 2,
+			},
+			"exhausted": {
+				backoff: b,
+				errs:    []error{io.ErrUnexpectedEOF, io.ErrUnexpectedEOF, io.ErrUnexpectedEOF, nil},
+				calls:   3,
+				retries: 2,
+				err:     io.ErrUnexpectedEOF,
+			},
+		}
+	)
+	for name, ts := range dt {
+		tt := ts
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			var (
+				calls int
+				ctx   = vcs.WithRetries(context.Background())
+			)
+			err := tt.backoff.Retry(ctx, func() error {
+				calls++
+				if calls > len(tt.errs) {
+					return nil
+				}
+				return tt.errs[calls-1]
+			})
+			are.True(errors.Is(err, tt.err))        // mismatch error
+			are.Equal(calls, tt.calls)              // mismatch calls
+			are.Equal(vcs.Retries(ctx), tt.retries) // mismatch retries
+		})
+	}
+}
+
+func TestBackoff_Retry_Canceled(t *testing.T) {
+	t.Parallel()
+	var (
+		are         = is.New(t)
+		calls       int
+		b           = vcs.Backoff{Attempts: 3, Base: time.Minute}
+		ctx, cancel = context.WithTimeout(context.Background(), 10*time.Millisecond)
+	)
+	defer cancel()
+	err := b.Retry(ctx, func() error {
+		calls++
+		return vcs.Temporary(errup.ErrFetch)
+	})
+	are.True(errors.Is(err, errup.ErrFetch)) // mismatch error
+	are.Equal(calls, 1)                      // the context stops the backoff
+}
+
+func TestIsTemporary(t *testing.T) {
+	t.Parallel()
+	var (
+		are = is.New(t)
+		dt  = map[string]struct {
+			in  error
+			out bool
+		}{
+			"default":  {},
+			"fetch":    {in: errup.ErrFetch},
+			"marked":   {in: vcs.Temporary(errup.ErrFetch), out: true},
+			"wrapped":  {in: fmt.Errorf("git: %w", vcs.Temporary(errup.ErrFetch)), out: true},
+			"reset":    {in: fmt.Errorf("read: %w", syscall.ECONNRESET), out: true},
+			"eof":      {in: io.ErrUnexpectedEOF, out: true},
+			"canceled": {in: context.Canceled},
+			"deadline": {in: vcs.Temporary(context.DeadlineExceeded)},
+			"timeout":  {in: timeoutError{}, out: true},
+		}
+	)
+	are.NoErr(vcs.Temporary(nil)) // unexpected error
+	for name, ts := range dt {
+		tt := ts
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			are.Equal(vcs.IsTemporary(tt.in), tt.out) // mismatch result
+		})
+	}
+}
+
+func TestIsTemporaryStatus(t *testing.T) {
+	t.Parallel()
+	are := is.New(t)
+	are.True(!vcs.IsTemporaryStatus(http.StatusOK))             // mismatch ok
+	are.True(!vcs.IsTemporaryStatus(http.StatusNotFound))       // mismatch not found
+	are.True(vcs.IsTemporaryStatus(http.StatusTooManyRequests)) // mismatch too many requests
+	are.True(vcs.IsTemporaryStatus(http.StatusBadGateway))      // mismatch bad gateway
+}
+
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
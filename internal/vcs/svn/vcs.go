@@ -0,0 +1,116 @@
+// Copyright (c) 2020 Hervé Gouchet. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+// Package svn provides methods to list the tags of a Subversion repository served over HTTP.
+package svn
+
+import (
+	"context"
+	"encoding/xml"
+	"io"
+	"net/url"
+	"strings"
+
+	"github.com/rvflash/goup/internal/errors"
+	"github.com/rvflash/goup/internal/semver"
+	"github.com/rvflash/goup/internal/vcs"
+)
+
+// Name is the name of this VCS.
+const Name = "svn"
+
+// tagsPath is the directory of the tags, by convention.
+const tagsPath = "/tags/"
+
+// VCS is a Subversion version control system.
+type VCS struct {
+	auth vcs.BasicAuthentifier
+	http vcs.ClientChooser
+}
+
+// New returns a new instance of VCS.
+func New(client vcs.ClientChooser, auth vcs.BasicAuthentifier) *VCS {
+	return &VCS{
+		auth: auth,
+		http: client,
+	}
+}
+
+// CanFetch implements the vcs.VCS interface.
+// The VCS of a repository can not be guessed with its path, so it's only used behind go-import metadata.
+func (s *VCS) CanFetch(_ string) bool {
+	return false
+}
+
+// FetchPath implements the vcs.VCS interface.
+func (s *VCS) FetchPath(ctx context.Context, path string) (semver.Tags, error) {
+	if path == "" {
+		return nil, errors.ErrRepository
+	}
+	return s.FetchURL(ctx, vcs.URLScheme(vcs.HTTPS)+path)
+}
+
+// FetchURL implements the vcs.VCS interface.
+// It lists the sub-directories of the tags directory, as served by Apache mod_dav_svn.
+func (s *VCS) FetchURL(ctx context.Context, rawURL string) (semver.Tags, error) {
+	if ctx == nil || s.http == nil {
+		return nil, errors.ErrSystem
+	}
+	u, err := url.Parse(strings.TrimSuffix(rawURL, "/") + tagsPath)
+	if err != nil || rawURL == "" {
+		return nil, vcs.Errorf(Name, errors.ErrRepository)
+	}
+	// Security check
+	if !vcs.IsSecureScheme(u.Scheme) && !s.http.AllowInsecure(vcs.RepoPath(u)) {
+		return nil, vcs.Errorf(Name, errors.ErrRepository, errors.NewSecurityIssue(u.String()))
+	}
+	body, err := vcs.Get(ctx, s.http, s.auth, Name, u.String())
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = body.Close() }()
+
+	return parseTags(body)
+}
+
+const (
+	anchor = "a"
+	href   = "href"
+	parent = "../"
+	slash  = "/"
+)
+
+// parseTags returns the tags behind the links of the directory listing.
+func parseTags(r io.Reader) (semver.Tags, error) {
+	d := xml.NewDecoder(r)
+	d.Strict = false
+	d.AutoClose = xml.HTMLAutoClose
+	d.Entity = xml.HTMLEntity
+	var res semver.Tags
+	for {
+		t, err := d.Token()
+		if err == io.EOF {
+			return res, nil
+		}
+		if err != nil {
+			return nil, vcs.Errorf(Name, errors.ErrFetch, err)
+		}
+		e, ok := t.(xml.StartElement)
+		if !ok || !strings.EqualFold(e.Name.Local, anchor) {
+			continue
+		}
+		for _, a := range e.Attr {
+			if !strings.EqualFold(a.Name.Local, href) || a.Value == parent || !strings.HasSuffix(a.Value, slash) {
+				continue
+			}
+			name, err := url.PathUnescape(strings.TrimSuffix(a.Value, slash))
+			if err != nil || strings.Contains(name, slash) {
+				continue
+			}
+			if v := semver.New(name); v.IsValid() {
+				res = append(res, v)
+			}
+		}
+	}
+}
@@ -0,0 +1,81 @@
+// Copyright (c) 2020 Hervé Gouchet. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package svn_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/matryer/is"
+	errup "github.com/rvflash/goup/internal/errors"
+	"github.com/rvflash/goup/internal/vcs"
+	"github.com/rvflash/goup/internal/vcs/svn"
+)
+
+const listing = `<html><head><title>repo - Revision 42: /tags</title></head>
+<body>
+ <h2>repo - Revision 42: /tags</h2>
+ <ul>
+  <li><a href="../">..</a></li>
+  <li><a href="v0.1.0/">v0.1.0/</a></li>
+  <li><a href="v0.2.0-rc.1/">v0.2.0-rc.1/</a></li>
+  <li><a href="release%201/">release 1/</a></li>
+  <li><a href="README">README</a></li>
+ </ul>
+ <hr noshade><em>Powered by <a href="http://subversion.apache.org/">Apache Subversion</a></em>
+</body></html>`
+
+func TestVCS_CanFetch(t *testing.T) {
+	t.Parallel()
+	is.New(t).True(!svn.New(nil, nil).CanFetch("example.com/repo")) // unexpected fetch
+}
+
+func TestVCS_FetchURL(t *testing.T) {
+	t.Parallel()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repo/tags/" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(listing))
+	}))
+	defer srv.Close()
+	var (
+		are = is.New(t)
+		dt  = map[string]struct {
+			cli vcs.ClientChooser
+			ctx context.Context
+			url string
+			out []string
+			err error
+		}{
+			"default":   {err: errup.ErrSystem},
+			"no url":    {cli: vcs.NewHTTPClient(time.Second, "127.0.0.1:*"), ctx: context.Background(), err: errup.ErrRepository},
+			"insecure":  {cli: vcs.NewHTTPClient(time.Second, ""), ctx: context.Background(), url: srv.URL + "/repo", err: errup.ErrRepository},
+			"not found": {cli: vcs.NewHTTPClient(time.Second, "127.0.0.1:*"), ctx: context.Background(), url: srv.URL + "/oops", err: errup.ErrNotFound},
+			"ok": {
+				cli: vcs.NewHTTPClient(time.Second, "127.0.0.1:*"),
+				ctx: context.Background(),
+				url: srv.URL + "/repo",
+				out: []string{"v0.1.0", "v0.2.0-rc.1"},
+			},
+		}
+	)
+	for name, ts := range dt {
+		tt := ts
+		t.Run(name, func(_ *testing.T) {
+			res, err := svn.New(tt.cli, nil).FetchURL(tt.ctx, tt.url)
+			are.True(errors.Is(err, tt.err)) // mismatch error
+			are.Equal(len(res), len(tt.out)) // mismatch length
+			for k, v := range res {
+				are.Equal(v.String(), tt.out[k]) // mismatch tag
+			}
+		})
+	}
+}
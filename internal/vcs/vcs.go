@@ -11,8 +11,12 @@ import (
 	"context"
 	"net/http"
 	"net/url"
+	"strings"
+	"time"
 
 	"github.com/rvflash/goup/internal/semver"
+
+	"golang.org/x/mod/module"
 )
 
 // System must be implemented by any VCS.
@@ -22,6 +26,37 @@ type System interface {
 	FetchURL(ctx context.Context, url string) (semver.Tags, error)
 }
 
+// ModFetcher must be implemented by any VCS able to read the go.mod file of a module version.
+// With FetchModURL, dir is the directory of the module in the repository behind the URL.
+type ModFetcher interface {
+	FetchMod(ctx context.Context, path, version string) ([]byte, error)
+	FetchModURL(ctx context.Context, url, dir, version string) ([]byte, error)
+}
+
+// HeadFetcher must be implemented by any VCS able to read the last commit of the default branch of a repository.
+type HeadFetcher interface {
+	FetchHead(ctx context.Context, path string) (*Revision, error)
+	FetchHeadURL(ctx context.Context, url string) (*Revision, error)
+}
+
+// ZipFetcher must be implemented by any VCS able to download the zip archive of a module version,
+// like a Go module proxy.
+type ZipFetcher interface {
+	FetchZip(ctx context.Context, path, version string) ([]byte, error)
+}
+
+// Revision is a commit of a repository.
+type Revision struct {
+	Hash string
+	Time time.Time
+}
+
+// Cache must be implemented to store the remote properties between two runs.
+type Cache interface {
+	Get(key string, v interface{}) bool
+	Set(key string, v interface{}) error
+}
+
 // BasicAuth contains basic auth properties.
 type BasicAuth struct {
 	Username string
@@ -70,6 +105,20 @@ func RepoPath(u *url.URL) string {
 	return u.Host + u.Path
 }
 
+// ModuleDir returns the subdirectory of the module in its repository, based on the root path of the repository.
+// The major version suffix of the module path is ignored: the tags of github.com/group/pkg/v2 are not prefixed.
+func ModuleDir(root, modulePath string) string {
+	prefix, _, ok := module.SplitPathVersion(modulePath)
+	if !ok {
+		prefix = modulePath
+	}
+	root = strings.TrimSuffix(root, "/")
+	if root == "" || !strings.HasPrefix(prefix, root+"/") {
+		return ""
+	}
+	return prefix[len(root)+1:]
+}
+
 // URLScheme returns the protocol scheme to use as prefix path for this scheme.
 func URLScheme(name string) string {
 	switch name {
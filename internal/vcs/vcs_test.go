@@ -37,6 +37,37 @@ func TestIsSecureScheme(t *testing.T) {
 	}
 }
 
+func TestModuleDir(t *testing.T) {
+	t.Parallel()
+	var (
+		are = is.New(t)
+		dt  = map[string]struct {
+			root string
+			path string
+			out  string
+		}{
+			"default": {},
+			"root":    {root: "golang.org/x/tools", path: "golang.org/x/tools"},
+			"nested":  {root: "golang.org/x/tools", path: "golang.org/x/tools/gopls", out: "gopls"},
+			"major":   {root: "github.com/group/pkg", path: "github.com/group/pkg/v2"},
+			"nested major": {
+				root: "github.com/group/pkg",
+				path: "github.com/group/pkg/sub/dir/v3",
+				out:  "sub/dir",
+			},
+			"other": {root: "golang.org/x/tools", path: "golang.org/x/toolsbox/gopls"},
+			"gopkg": {root: "gopkg.in/yaml.v3", path: "gopkg.in/yaml.v3"},
+		}
+	)
+	for name, ts := range dt {
+		tt := ts
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			are.Equal(vcs.ModuleDir(tt.root, tt.path), tt.out) // mismatch result
+		})
+	}
+}
+
 func TestRepoPath(t *testing.T) {
 	t.Parallel()
 	var (
@@ -17,7 +17,9 @@ import (
 	"github.com/rvflash/goup/internal/app"
 	errs "github.com/rvflash/goup/internal/errors"
 	"github.com/rvflash/goup/internal/log"
+	"github.com/rvflash/goup/internal/report"
 	"github.com/rvflash/goup/internal/signal"
+	"github.com/rvflash/goup/internal/vcs/goproxy"
 	"github.com/rvflash/goup/pkg/goup"
 )
 
@@ -28,44 +30,132 @@ const (
 	comma      = ","
 	errorCode  = 1
 	goInsecure = "GOINSECURE"
+	goNoProxy  = "GONOPROXY"
+	goNoSumDB  = "GONOSUMDB"
 	goPrivate  = "GOPRIVATE"
+	goProxy    = "GOPROXY"
+	goSumDB    = "GOSUMDB"
+	jobs       = 4
+	rateLimit  = 10
+	requests   = 16
+	retries    = 2
+	depTimeout = 20 * time.Second
 	timeout    = time.Minute
 )
 
+// Flags of the update modes.
+const (
+	majorFlag      = "M"
+	majorMinorFlag = "m"
+)
+
 func main() {
 	var (
 		c = goup.Config{
+			Format:           report.Text,
 			InsecurePatterns: patterns(os.Getenv(goInsecure), os.Getenv(goPrivate)),
+			NoProxyPatterns:  patterns(firstOf(os.Getenv(goNoProxy), os.Getenv(goPrivate))),
+			NoSumDBPatterns:  patterns(firstOf(os.Getenv(goNoSumDB), os.Getenv(goPrivate))),
+			ProxyURLs:        firstOf(os.Getenv(goProxy), goproxy.DefaultList),
+			SumDB:            os.Getenv(goSumDB),
+			Jobs:             jobs,
+			MaxRequests:      requests,
+			RateLimit:        rateLimit,
+			Retries:          retries,
+			DepTimeout:       depTimeout,
+			Timeout:          timeout,
 		}
-		s = "exclude indirect modules"
 		l = log.New(os.Stderr, isatty.IsTerminal(os.Stderr.Fd()))
 	)
-	flag.BoolVar(&c.ExcludeIndirect, "i", false, s)
+	bind(flag.CommandLine, &c)
+	flag.Parse()
+	l.SetVerbose(c.Verbose)
+
+	err := run(signal.Background(), c, flag.Args(), l, overrider(flag.CommandLine))
+	if err != nil {
+		if !errors.Is(err, errs.ErrMod) {
+			l.Errorf(err.Error())
+		}
+		os.Exit(errorCode)
+	}
+}
+
+// bind defines the flags of the command line on the given configuration.
+// The current values of the configuration are used as default values.
+func bind(fs *flag.FlagSet, c *goup.Config) {
+	s := "exclude indirect modules"
+	fs.BoolVar(&c.ExcludeIndirect, "i", c.ExcludeIndirect, s)
 	s = "exit on first error occurred"
-	flag.BoolVar(&c.Strict, "s", false, s)
+	fs.BoolVar(&c.Strict, "s", c.Strict, s)
 	s = "ensure to have the latest major version"
-	flag.BoolVar(&c.Major, "M", false, s)
+	fs.BoolVar(&c.Major, majorFlag, c.Major, s)
 	s = "ensure to have the latest couple major with minor version"
-	flag.BoolVar(&c.MajorMinor, "m", false, s)
+	fs.BoolVar(&c.MajorMinor, majorMinorFlag, c.MajorMinor, s)
+	s = "check if the default branch has moved on since the commit of the pseudo-versions"
+	fs.BoolVar(&c.CheckHead, "head", c.CheckHead, s)
 	s = "comma-separated list of glob patterns to match the repository paths where to force tag usage."
-	flag.StringVar(&c.OnlyReleases, "r", "", s)
+	fs.StringVar(&c.OnlyReleases, "r", c.OnlyReleases, s)
 	s = "maximum time duration"
-	flag.DurationVar(&c.Timeout, "t", timeout, s)
+	fs.DurationVar(&c.Timeout, "t", c.Timeout, s)
+	s = "maximum time duration to check one dependency, unlimited with 0"
+	fs.DurationVar(&c.DepTimeout, "dep-timeout", c.DepTimeout, s)
+	s = "number of retries of a remote request failed with a temporary error"
+	fs.IntVar(&c.Retries, "retries", c.Retries, s)
+	s = "maximum number of go.mod files checked at the same time"
+	fs.IntVar(&c.Jobs, "j", c.Jobs, s)
+	s = "maximum number of remote requests in progress at the same time, unlimited with 0"
+	fs.IntVar(&c.MaxRequests, "max-requests", c.MaxRequests, s)
+	s = "maximum number of requests per second sent to a same host, unlimited with 0"
+	fs.Float64Var(&c.RateLimit, "rate-limit", c.RateLimit, s)
 	s = "force the update of the go.mod file as advised"
-	flag.BoolVar(&c.ForceUpdate, "f", false, s)
+	fs.BoolVar(&c.ForceUpdate, "f", c.ForceUpdate, s)
+	s = "with -f or -diff, also update the go and toolchain directives as advised"
+	fs.BoolVar(&c.UpdateDirectives, "directives", c.UpdateDirectives, s)
+	s = "print the changes advised on the go.mod file as a unified diff, without writing it"
+	fs.BoolVar(&c.Diff, "diff", c.Diff, s)
+	s = "comma-separated list of glob patterns to match the module paths to move to their latest major version, with their imports"
+	fs.StringVar(&c.Rewrite, "rewrite", c.Rewrite, s)
+	s = "verify the proposed versions against the checksum database (GOSUMDB)"
+	fs.BoolVar(&c.VerifySum, "verify", c.VerifySum, s)
+	s = "align the dependencies required at different versions by the go.mod files on the highest one in use or the latest one"
+	fs.StringVar(&c.Align, "align", c.Align, s)
+	s = "output format: text, json or sarif"
+	fs.StringVar(&c.Format, "format", c.Format, s)
+	s = "time duration to keep the remote tags in the user cache directory, disabled by default"
+	fs.DurationVar(&c.CacheTTL, "cache-ttl", c.CacheTTL, s)
+	s = "print the effective configuration of each go.mod file and exit"
+	fs.BoolVar(&c.PrintConfig, "print-config", c.PrintConfig, s)
 	s = "print version"
-	flag.BoolVar(&c.PrintVersion, "V", false, s)
+	fs.BoolVar(&c.PrintVersion, "V", c.PrintVersion, s)
 	s = "verbose output"
-	flag.BoolVar(&c.Verbose, "v", false, s)
-	flag.Parse()
-	l.SetVerbose(c.Verbose)
+	fs.BoolVar(&c.Verbose, "v", c.Verbose, s)
+}
 
-	err := run(signal.Background(), c, flag.Args(), l)
-	if err != nil {
-		if !errors.Is(err, errs.ErrMod) {
-			l.Errorf(err.Error())
+// overrider returns a function applying the flags explicitly set on the command line.
+// It allows flags to override the settings of the configuration file.
+func overrider(fs *flag.FlagSet) func(c *goup.Config) error {
+	set := make(map[string]string)
+	fs.Visit(func(f *flag.Flag) {
+		set[f.Name] = f.Value.String()
+	})
+	return func(c *goup.Config) error {
+		ffs := flag.NewFlagSet(fs.Name(), flag.ContinueOnError)
+		bind(ffs, c)
+		for name, value := range set {
+			if err := ffs.Set(name, value); err != nil {
+				return err
+			}
 		}
-		os.Exit(errorCode)
+		// The update mode flags are exclusive: the one set overrides the mode of the file.
+		_, major := set[majorFlag]
+		_, minor := set[majorMinorFlag]
+		switch {
+		case major && c.Major:
+			c.MajorMinor = false
+		case minor && c.MajorMinor:
+			c.Major = false
+		}
+		return nil
 	}
 }
 
@@ -79,10 +169,21 @@ func patterns(v ...string) string {
 	return strings.Join(a, comma)
 }
 
-func run(ctx context.Context, cnf goup.Config, args []string, out log.Printer) error {
+func firstOf(v ...string) string {
+	for _, s := range v {
+		if s = strings.TrimSpace(s); s != "" {
+			return s
+		}
+	}
+	return ""
+}
+
+func run(ctx context.Context, cnf goup.Config, args []string, out log.Printer, flags func(*goup.Config) error) error {
 	a, err := app.Open(
 		buildVersion,
 		app.WithLogger(out),
+		app.WithOutput(os.Stdout),
+		app.WithFlags(flags),
 	)
 	if err != nil {
 		return err
@@ -7,8 +7,10 @@ package main
 import (
 	"context"
 	"errors"
+	"flag"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/matryer/is"
 	errup "github.com/rvflash/goup/internal/errors"
@@ -37,6 +39,53 @@ func TestPatterns(t *testing.T) {
 	}
 }
 
+func TestFirstOf(t *testing.T) {
+	t.Parallel()
+	var (
+		are = is.New(t)
+		dt  = map[string]struct {
+			in  []string
+			out string
+		}{
+			"Default": {},
+			"Ok":      {in: []string{"", " ", " a ", "b"}, out: "a"},
+		}
+	)
+	for name, ts := range dt {
+		tt := ts
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			are.Equal(tt.out, firstOf(tt.in...)) // mismatch result
+		})
+	}
+}
+
+func TestOverrider(t *testing.T) {
+	t.Parallel()
+	var (
+		are = is.New(t)
+		f   = goup.Config{Timeout: time.Second}
+		fs  = flag.NewFlagSet("goup", flag.ContinueOnError)
+	)
+	bind(fs, &f)
+	are.NoErr(fs.Parse([]string{"-m", "-r", "example.com/*"})) // mismatch error
+	// Settings from the configuration file.
+	c := goup.Config{Major: true, Strict: true, Timeout: time.Minute}
+	are.NoErr(overrider(fs)(&c))               // mismatch error
+	are.True(c.MajorMinor)                     // expected flag
+	are.True(!c.Major)                         // the mode flag overrides the mode of the file
+	are.True(c.Strict)                         // expected file
+	are.Equal(c.OnlyReleases, "example.com/*") // mismatch flag
+	are.Equal(c.Timeout, time.Minute)          // mismatch file
+
+	fs = flag.NewFlagSet("goup", flag.ContinueOnError)
+	bind(fs, &f)
+	are.NoErr(fs.Parse([]string{"-M"})) // mismatch error
+	c = goup.Config{MajorMinor: true}
+	are.NoErr(overrider(fs)(&c))       // mismatch error
+	are.True(c.Major && !c.MajorMinor) // the mode flag overrides the mode of the file
+}
+
 func TestRun(t *testing.T) {
 	t.Parallel()
 	var (
@@ -59,8 +108,12 @@ func TestRun(t *testing.T) {
 		tt := ts
 		t.Run(name, func(t *testing.T) {
 			t.Parallel()
-			err := run(tt.ctx, tt.cnf, tt.args, tt.stderr)
+			err := run(tt.ctx, tt.cnf, tt.args, tt.stderr, noFlags)
 			are.True(errors.Is(err, tt.err)) // mismatch error
 		})
 	}
 }
+
+func noFlags(*goup.Config) error {
+	return nil
+}
@@ -0,0 +1,135 @@
+// Copyright (c) 2020 Hervé Gouchet. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package goup
+
+import (
+	"sort"
+
+	"github.com/rvflash/goup/internal/semver"
+	"github.com/rvflash/goup/pkg/mod"
+)
+
+// List of targets of the alignment.
+const (
+	// AlignHighest aligns the dependencies on the highest version in use.
+	AlignHighest = "highest"
+	// AlignLatest aligns the dependencies on the latest version allowed by the checks, if higher.
+	AlignLatest = "latest"
+)
+
+// NewAligner returns a new instance of Aligner, aligning the dependencies on this target.
+func NewAligner(target string) *Aligner {
+	return &Aligner{
+		target: target,
+		uses:   make(map[mod.Mod]map[string]string),
+		latest: make(map[string]string),
+	}
+}
+
+// Aligner aggregates the dependencies required by several go.mod files, like those of a monorepo,
+// to align each dependency required at different versions on a single one.
+type Aligner struct {
+	target string
+	uses   map[mod.Mod]map[string]string
+	latest map[string]string
+}
+
+// Add adds the go.mod file with the messages of its check.
+// The versions updated by the check are used instead of the ones of the file,
+// and the versions advised are the candidates of the latest target.
+// The versions advised under another module path, like a new major version, are ignored.
+// The replaced dependencies are ignored.
+func (a *Aligner) Add(file mod.Mod, messages []Message) {
+	if a == nil || file == nil {
+		return
+	}
+	uses := make(map[string]string)
+	for _, dep := range file.Dependencies() {
+		if !dep.Replacement() && dep.Version() != nil {
+			uses[dep.Path()] = dep.Version().String()
+		}
+	}
+	for _, msg := range messages {
+		v := msg.NewVersion()
+		if _, ok := uses[msg.Path()]; !ok || v == "" || msg.NewPath() != "" {
+			continue
+		}
+		if msg.Status() == Updated {
+			uses[msg.Path()] = v
+		}
+		if w, ok := a.latest[msg.Path()]; !ok || newer(v, w) {
+			a.latest[msg.Path()] = v
+		}
+	}
+	a.uses[file] = uses
+}
+
+// Align returns the messages aligning the dependencies of this go.mod file required at different versions
+// by the files added. With update, the go.mod file is updated in memory.
+func (a *Aligner) Align(file mod.Mod, update bool) []Message {
+	if a == nil {
+		return nil
+	}
+	uses, ok := a.uses[file]
+	if !ok {
+		return nil
+	}
+	paths := make([]string, 0, len(uses))
+	for p := range uses {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+
+	var res []Message
+	for _, p := range paths {
+		v := a.version(p)
+		if v == "" || v == uses[p] {
+			continue
+		}
+		e := newMisaligned(p, uses[p], v)
+		if update {
+			if err := file.UpdateRequire(p, v); err != nil {
+				e = newAlignFailure(err, p, uses[p])
+			} else {
+				e = newAlignUpdate(p, uses[p], v)
+			}
+		}
+		e.FileName, e.ModPath = file.Name(), file.Module()
+		res = append(res, e)
+	}
+	return res
+}
+
+// version returns the version to use for this dependency path.
+// It returns an empty string if the dependency is not required at different versions.
+func (a *Aligner) version(path string) string {
+	var (
+		res   string
+		count int
+		seen  = make(map[string]bool)
+	)
+	for _, uses := range a.uses {
+		v, ok := uses[path]
+		if !ok || seen[v] {
+			continue
+		}
+		seen[v] = true
+		count++
+		if res == "" || newer(v, res) {
+			res = v
+		}
+	}
+	if count < 2 {
+		return ""
+	}
+	if w, ok := a.latest[path]; ok && a.target == AlignLatest && newer(w, res) {
+		return w
+	}
+	return res
+}
+
+func newer(v, w string) bool {
+	return semver.Compare(semver.New(v), semver.New(w)) > 0
+}
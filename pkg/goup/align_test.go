@@ -0,0 +1,87 @@
+// Copyright (c) 2020 Hervé Gouchet. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package goup_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/matryer/is"
+	"github.com/rvflash/goup/pkg/goup"
+	"github.com/rvflash/goup/pkg/mod"
+)
+
+const matryer = "github.com/matryer/is"
+
+func TestAligner_Align(t *testing.T) {
+	t.Parallel()
+	var (
+		are = is.New(t)
+		dt  = map[string]struct {
+			target string
+			latest string
+			path   string
+			update bool
+			out    string
+			status goup.Status
+		}{
+			"highest":        {target: goup.AlignHighest, out: "v1.4.1", status: goup.Drifted},
+			"latest":         {target: goup.AlignLatest, latest: "v1.4.2", out: "v1.4.2", status: goup.Drifted},
+			"latest in use":  {target: goup.AlignLatest, out: "v1.4.1", status: goup.Drifted},
+			"major path":     {target: goup.AlignLatest, latest: "v2.0.0", path: matryer + "/v2", out: "v1.4.1", status: goup.Drifted},
+			"highest update": {target: goup.AlignHighest, update: true, out: "v1.4.1", status: goup.Updated},
+		}
+	)
+	for name, ts := range dt {
+		tt := ts
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			a, b := parseWork(t)
+			al := goup.NewAligner(tt.target)
+			al.Add(a, nil)
+			var msgs []goup.Message
+			if tt.latest != "" {
+				msgs = append(msgs, &goup.Entry{Dep: matryer, Current: "v1.4.1", Proposed: tt.latest, ProposedPath: tt.path, State: goup.Outdated})
+			}
+			al.Add(b, msgs)
+			res := al.Align(a, tt.update)
+			are.Equal(len(res), 1)                                     // mismatch number of messages
+			are.Equal(res[0].Path(), matryer)                          // mismatch path
+			are.Equal(res[0].Version(), "v1.2.0")                      // mismatch version
+			are.Equal(res[0].NewVersion(), tt.out)                     // mismatch target
+			are.Equal(res[0].Status(), tt.status)                      // mismatch status
+			are.Equal(len(al.Align(b, false)) > 0, tt.out != "v1.4.1") // mismatch aligned file
+		})
+	}
+}
+
+func TestAligner_Add(t *testing.T) {
+	t.Parallel()
+	var (
+		are = is.New(t)
+		al  *goup.Aligner
+	)
+	a, b := parseWork(t)
+	al.Add(a, nil)
+	are.Equal(al.Align(a, false), nil) // mismatch default
+	al = goup.NewAligner(goup.AlignHighest)
+	al.Add(a, []goup.Message{&goup.Entry{Dep: matryer, Current: "v1.2.0", Proposed: "v1.4.1", State: goup.Updated}})
+	al.Add(b, nil)
+	are.Equal(len(al.Align(a, false)), 0) // already updated
+	are.Equal(len(al.Align(b, false)), 0) // already aligned
+}
+
+func parseWork(t *testing.T) (a, b *mod.File) {
+	t.Helper()
+	var err error
+	dir := filepath.Join("..", "..", "testdata", "golden", "work")
+	if a, err = mod.Parse(filepath.Join(dir, "a", mod.Filename)); err != nil {
+		t.Fatal(err)
+	}
+	if b, err = mod.Parse(filepath.Join(dir, "b", mod.Filename)); err != nil {
+		t.Fatal(err)
+	}
+	return a, b
+}
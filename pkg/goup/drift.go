@@ -0,0 +1,46 @@
+// Copyright (c) 2020 Hervé Gouchet. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package goup
+
+import (
+	"sort"
+
+	"github.com/rvflash/goup/pkg/mod"
+)
+
+// Drift reports each dependency required at different versions by these go.mod files,
+// like the modules of a workspace. The replaced dependencies are ignored.
+func Drift(files []mod.Mod) []Message {
+	var (
+		paths []string
+		uses  = make(map[string]map[string][]string)
+	)
+	for _, f := range files {
+		if f == nil {
+			continue
+		}
+		for _, dep := range f.Dependencies() {
+			if dep.Replacement() || dep.Version() == nil {
+				continue
+			}
+			p := dep.Path()
+			if _, ok := uses[p]; !ok {
+				uses[p] = make(map[string][]string)
+				paths = append(paths, p)
+			}
+			v := dep.Version().String()
+			uses[p][v] = append(uses[p][v], f.Module())
+		}
+	}
+	sort.Strings(paths)
+
+	var res []Message
+	for _, p := range paths {
+		if len(uses[p]) > 1 {
+			res = append(res, newDrift(p, uses[p]))
+		}
+	}
+	return res
+}
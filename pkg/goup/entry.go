@@ -5,6 +5,16 @@
 package goup
 
 import (
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	errs "github.com/rvflash/goup/internal/errors"
+	"github.com/rvflash/goup/internal/release"
+	"github.com/rvflash/goup/internal/semver"
+	"github.com/rvflash/goup/internal/sum"
 	"github.com/rvflash/goup/pkg/mod"
 )
 
@@ -24,11 +34,23 @@ const (
 )
 
 // Message exposes Entry properties.
+// Format and Args describe the message to print, the other methods its structured properties,
+// so a program can act on the result of a check without parsing it.
 type Message interface {
 	Args() []interface{}
+	Directive() bool
+	Err() error
+	File() string
 	Format() string
 	Level() Level
+	Module() string
+	NewPath() string
+	NewVersion() string
 	OutDated() (newVersion string, ok bool)
+	Path() string
+	Status() Status
+	UpdateKind() UpdateKind
+	Version() string
 }
 
 // NewEntry returns a new Entry.
@@ -41,10 +63,24 @@ func NewEntry(level Level, format string, a ...interface{}) *Entry {
 }
 
 // Entry represents a message.
+// Dep, Current, Proposed, ProposedPath, State and Cause are the structured properties of the message.
+// They are only defined when the message concerns a dependency.
+// ProposedPath is only defined when the proposed version is published under a new module path.
+// FileName and ModPath locate the go.mod file checked, they are defined by the checker.
+// IsDirective is true when the message concerns the go or toolchain directive named by Dep, not a dependency.
 type Entry struct {
-	Kind    Level
-	Message string
-	Data    []interface{}
+	Kind         Level
+	Message      string
+	Data         []interface{}
+	Dep          string
+	Current      string
+	Proposed     string
+	ProposedPath string
+	State        Status
+	Cause        error
+	FileName     string
+	ModPath      string
+	IsDirective  bool
 }
 
 // Args implements the Message interface.
@@ -55,6 +91,32 @@ func (e *Entry) Args() []interface{} {
 	return e.Data
 }
 
+// Directive implements the Message interface.
+// It returns true if the message concerns the go or toolchain directive of the go.mod file.
+func (e *Entry) Directive() bool {
+	if e == nil {
+		return false
+	}
+	return e.IsDirective
+}
+
+// Err implements the Message interface.
+func (e *Entry) Err() error {
+	if e == nil {
+		return nil
+	}
+	return e.Cause
+}
+
+// File implements the Message interface.
+// It returns the name of the go.mod file checked.
+func (e *Entry) File() string {
+	if e == nil {
+		return ""
+	}
+	return e.FileName
+}
+
 // Format implements the Message interface.
 func (e *Entry) Format() string {
 	if e == nil {
@@ -71,54 +133,345 @@ func (e *Entry) Level() Level {
 	return e.Kind
 }
 
+// Module implements the Message interface.
+// It returns the module path of the go.mod file checked.
+func (e *Entry) Module() string {
+	if e == nil {
+		return ""
+	}
+	return e.ModPath
+}
+
+// NewPath implements the Message interface.
+// It returns the module path of the new version when it differs from the one of the dependency,
+// like example.com/lib/v3 for a new major version of example.com/lib/v2.
+func (e *Entry) NewPath() string {
+	if e == nil {
+		return ""
+	}
+	return e.ProposedPath
+}
+
+// NewVersion implements the Message interface.
+func (e *Entry) NewVersion() string {
+	if e == nil {
+		return ""
+	}
+	return e.Proposed
+}
+
+// Path implements the Message interface.
+func (e *Entry) Path() string {
+	if e == nil {
+		return ""
+	}
+	return e.Dep
+}
+
+// Status implements the Message interface.
+func (e *Entry) Status() Status {
+	if e == nil {
+		return Failed
+	}
+	return e.State
+}
+
+// UpdateKind implements the Message interface.
+// It returns NoUpdate if no newer version is proposed.
+func (e *Entry) UpdateKind() UpdateKind {
+	if e == nil || e.Proposed == "" {
+		return NoUpdate
+	}
+	v, w := e.Current, e.Proposed
+	if e.IsDirective {
+		// The Go versions are not semantic versions.
+		v, w = release.Semver(v), release.Semver(w)
+	}
+	return UpdateKind(semver.Kind(semver.New(v), semver.New(w)))
+}
+
+// Version implements the Message interface.
+func (e *Entry) Version() string {
+	if e == nil {
+		return ""
+	}
+	return e.Current
+}
+
 // OutDated implements the Message interface.
+// It returns the version to use instead of the current one, if the dependency is outdated or retracted.
 func (e *Entry) OutDated() (newVersion string, ok bool) {
-	const newVersionPos = 2
-	if e == nil || e.Level() != WarnLevel || len(e.Args()) != newVersionPos+1 {
+	if e == nil || e.Proposed == "" {
+		return
+	}
+	switch e.State {
+	case Outdated, Retracted:
+		return e.Proposed, true
+	default:
 		return
 	}
-	newVersion, ok = e.Args()[newVersionPos].(string)
-	return
+}
+
+func newAbort(dep mod.Module) *Entry {
+	if dep == nil {
+		return nil
+	}
+	e := &Entry{Dep: dep.Path(), Current: dep.Version().String(), State: Aborted}
+	return e.log(DebugLevel, "%s: %s check aborted", e.Dep, e.Current)
 }
 
 func newCheck(dep mod.Module) *Entry {
 	if dep == nil {
 		return nil
 	}
-	return NewEntry(DebugLevel, "%s: %s is up to date", dep.Path(), dep.Version().String())
+	e := &Entry{Dep: dep.Path(), Current: dep.Version().String(), State: UpToDate}
+	return e.log(DebugLevel, "%s: %s is up to date", e.Dep, e.Current)
 }
 
 func newError(err error, file mod.Mod) *Entry {
 	if err == nil || file == nil {
 		return nil
 	}
-	return NewEntry(ErrorLevel, "%s: "+err.Error(), file.Module())
+	e := &Entry{State: Failed, Cause: err}
+	return e.log(ErrorLevel, "%s: "+err.Error(), file.Module())
 }
 
 func newFailure(err error, dep mod.Module) *Entry {
 	if err == nil || dep == nil {
 		return nil
 	}
-	return NewEntry(ErrorLevel, "%s: check failed: %s", dep.Path(), err)
+	e := &Entry{Dep: dep.Path(), Current: dep.Version().String(), State: Failed, Cause: err}
+	return e.log(ErrorLevel, "%s: check failed: %s", e.Dep, err)
 }
 
-func newSkip(dep mod.Module) *Entry {
+// newRetries only concerns the debug output, so it is not attached to the dependency.
+func newRetries(dep mod.Module, retries int) *Entry {
+	if dep == nil || retries < 1 {
+		return nil
+	}
+	return NewEntry(DebugLevel, "%s: %s checked after %s attempt(s), %s retried on temporary failures",
+		dep.Path(), dep.Version().String(), strconv.Itoa(retries+1), strconv.Itoa(retries))
+}
+
+func newSkip(dep mod.Module, reason string) *Entry {
 	if dep == nil {
 		return nil
 	}
-	return NewEntry(DebugLevel, "%s: %s update skipped: indirect", dep.Path(), dep.Version().String())
+	e := &Entry{Dep: dep.Path(), Current: dep.Version().String(), State: Skipped}
+	return e.log(DebugLevel, "%s: %s update skipped: "+reason, e.Dep, e.Current)
 }
 
 func newUpdate(dep mod.Module, newVersion string) *Entry {
 	if dep == nil {
 		return nil
 	}
-	return NewEntry(InfoLevel, "%s: %s will be updated to %s", dep.Path(), dep.Version().String(), newVersion)
+	e := &Entry{Dep: dep.Path(), Current: dep.Version().String(), Proposed: newVersion, State: Updated}
+	return e.log(InfoLevel, "%s: %s will be updated to %s", e.Dep, e.Current, newVersion)
 }
 
 func newOutOfDate(dep mod.Module, newVersion string) *Entry {
 	if dep == nil {
 		return nil
 	}
-	return NewEntry(WarnLevel, "%s: %s must be updated to %s", dep.Path(), dep.Version().String(), newVersion)
+	e := &Entry{Dep: dep.Path(), Current: dep.Version().String(), Proposed: newVersion, State: Outdated}
+	return e.log(WarnLevel, "%s: %s must be updated to %s", e.Dep, e.Current, newVersion)
+}
+
+func newPseudo(dep mod.Module, pv semver.PseudoVersion, now time.Time) *Entry {
+	if dep == nil {
+		return nil
+	}
+	e := &Entry{Dep: dep.Path(), Current: dep.Version().String(), State: UpToDate}
+	return e.log(InfoLevel, "%s: %s is a pseudo-version of the commit %s, %s day(s) old",
+		e.Dep, e.Current, pv.Rev, strconv.Itoa(pv.Days(now)))
+}
+
+func newPseudoOutOfDate(dep mod.Module, pv semver.PseudoVersion, now time.Time, newVersion string) *Entry {
+	if dep == nil {
+		return nil
+	}
+	e := &Entry{Dep: dep.Path(), Current: dep.Version().String(), Proposed: newVersion, State: Outdated}
+	format := "%s: %s is a pseudo-version of the commit %s, %s day(s) old, must be updated to "
+	if _, ok := semver.ParsePseudo(semver.New(newVersion)); ok {
+		format += "the last commit %s"
+	} else {
+		format += "the release %s"
+	}
+	return e.log(WarnLevel, format, e.Dep, e.Current, pv.Rev, strconv.Itoa(pv.Days(now)), newVersion)
+}
+
+func newMajorAvailable(dep mod.Module, newPath, newVersion string) *Entry {
+	if dep == nil || newPath == "" {
+		return nil
+	}
+	e := &Entry{
+		Dep:          dep.Path(),
+		Current:      dep.Version().String(),
+		Proposed:     newVersion,
+		ProposedPath: newPath,
+		State:        MajorAvailable,
+	}
+	return e.log(WarnLevel, "%s: %s, major available: %s %s", e.Dep, e.Current, newPath, newVersion)
+}
+
+func newMajorUpdate(dep mod.Module, newPath, newVersion string) *Entry {
+	if dep == nil || newPath == "" {
+		return nil
+	}
+	e := &Entry{
+		Dep:          dep.Path(),
+		Current:      dep.Version().String(),
+		Proposed:     newVersion,
+		ProposedPath: newPath,
+		State:        Updated,
+	}
+	return e.log(InfoLevel, "%s: %s will be updated to %s %s", e.Dep, e.Current, newPath, newVersion)
+}
+
+// newRewrite lists the Go files whose imports of the old module path are rewritten, or to rewrite.
+func newRewrite(oldPath, newPath string, files []string, written bool) *Entry {
+	if oldPath == "" || newPath == "" {
+		return nil
+	}
+	format := "%s: imports to rewrite with %s in %s file(s): %s"
+	if written {
+		format = "%s: imports rewritten with %s in %s file(s): %s"
+	}
+	return NewEntry(InfoLevel, format, oldPath, newPath, strconv.Itoa(len(files)), strings.Join(files, ", "))
+}
+
+// newSumFailure only concerns the go.sum file, so it is not attached to the dependency.
+// A hash unknown by the checksum database is an error.
+func newSumFailure(err error, path, version string) *Entry {
+	if err == nil || path == "" {
+		return nil
+	}
+	level := WarnLevel
+	if errors.Is(err, errs.ErrChecksum) {
+		level = ErrorLevel
+	}
+	return NewEntry(level, "%s: %s hash not added to %s: %s", path, version, sum.Filename, err)
+}
+
+func newSumUpdate(file mod.Mod, added, removed int) *Entry {
+	if file == nil {
+		return nil
+	}
+	return NewEntry(InfoLevel, "%s: %s updated with %s line(s) added and %s removed",
+		file.Module(), sum.Filename, strconv.Itoa(added), strconv.Itoa(removed))
+}
+
+func newUpstreamSkip(dep mod.Module) *Entry {
+	return NewEntry(DebugLevel, "%s: go.mod not read on a Go module proxy, retractions and deprecation not checked", dep.Path())
+}
+
+func newUnverified(err error, dep mod.Module, newPath, newVersion string) *Entry {
+	if err == nil || dep == nil {
+		return nil
+	}
+	e := &Entry{Dep: dep.Path(), Current: dep.Version().String(), State: Failed, Cause: err}
+	return e.log(ErrorLevel, "%s: %s %s not verified by the checksum database: %s", e.Dep, newPath, newVersion, err)
+}
+
+func newDeprecated(dep mod.Module, up *mod.Upstream, level Level) *Entry {
+	if dep == nil || up == nil || up.Deprecated == "" {
+		return nil
+	}
+	e := &Entry{Dep: dep.Path(), Current: dep.Version().String(), State: Deprecated}
+	return e.log(level, "%s: module is deprecated: "+strings.ReplaceAll(up.Deprecated, "%", "%%"), e.Dep)
+}
+
+func newRetracted(dep mod.Module, newVersion, rationale string) *Entry {
+	if dep == nil {
+		return nil
+	}
+	e := &Entry{Dep: dep.Path(), Current: dep.Version().String(), Proposed: newVersion, State: Retracted}
+	format := "%s: %s is retracted"
+	if rationale != "" {
+		format += ": " + strings.ReplaceAll(rationale, "%", "%%")
+	}
+	if newVersion == "" {
+		return e.log(WarnLevel, format, e.Dep, e.Current)
+	}
+	return e.log(WarnLevel, format+", must be updated to %s", e.Dep, e.Current, newVersion)
+}
+
+// newDrift returns the entry of a dependency required at different versions,
+// listed with the modules using them, from the oldest to the newest.
+func newDrift(path string, uses map[string][]string) *Entry {
+	if path == "" || len(uses) == 0 {
+		return nil
+	}
+	versions := make([]string, 0, len(uses))
+	for v := range uses {
+		versions = append(versions, v)
+	}
+	sort.Slice(versions, func(i, j int) bool {
+		return semver.Compare(semver.New(versions[i]), semver.New(versions[j])) < 0
+	})
+	desc := make([]string, len(versions))
+	for k, v := range versions {
+		modules := append([]string(nil), uses[v]...)
+		sort.Strings(modules)
+		desc[k] = v + " by " + strings.Join(modules, ", ")
+	}
+	e := &Entry{Dep: path, Current: versions[0], State: Drifted}
+	return e.log(WarnLevel, "%s: required at different versions: "+strings.ReplaceAll(strings.Join(desc, "; "), "%", "%%"), e.Dep)
+}
+
+func newMisaligned(path, version, newVersion string) *Entry {
+	e := &Entry{Dep: path, Current: version, Proposed: newVersion, State: Drifted}
+	return e.log(WarnLevel, "%s: %s must be aligned to %s", e.Dep, e.Current, newVersion)
+}
+
+func newAlignFailure(err error, path, version string) *Entry {
+	e := &Entry{Dep: path, Current: version, State: Failed, Cause: err}
+	return e.log(ErrorLevel, "%s: alignment failed: %s", e.Dep, err)
+}
+
+func newAlignUpdate(path, version, newVersion string) *Entry {
+	e := &Entry{Dep: path, Current: version, Proposed: newVersion, State: Updated}
+	return e.log(InfoLevel, "%s: %s will be aligned to %s", e.Dep, e.Current, newVersion)
+}
+
+// List of the directives of the go.mod file checked against the Go releases.
+const (
+	goDirective        = "go"
+	toolchainDirective = "toolchain"
+	toolchainPrefix    = "go"
+)
+
+func newDirectiveCheck(name, version string) *Entry {
+	e := &Entry{Dep: name, Current: version, State: UpToDate, IsDirective: true}
+	return e.log(DebugLevel, "%s: %s is up to date", e.Dep, e.Current)
+}
+
+func newDirectiveFailure(err error, name, version string) *Entry {
+	e := &Entry{Dep: name, Current: version, State: Failed, Cause: err, IsDirective: true}
+	return e.log(ErrorLevel, "%s: check failed: %s", e.Dep, err)
+}
+
+func newDirectiveOutOfDate(name, version, newVersion string) *Entry {
+	e := &Entry{Dep: name, Current: version, Proposed: newVersion, State: Outdated, IsDirective: true}
+	return e.log(WarnLevel, "%s: %s must be updated to %s", e.Dep, e.Current, newVersion)
+}
+
+func newDirectiveUpdate(name, version, newVersion string) *Entry {
+	e := &Entry{Dep: name, Current: version, Proposed: newVersion, State: Updated, IsDirective: true}
+	return e.log(InfoLevel, "%s: %s will be updated to %s", e.Dep, e.Current, newVersion)
+}
+
+func newUnsupported(version, newVersion string) *Entry {
+	e := &Entry{Dep: goDirective, Current: version, Proposed: newVersion, State: Outdated, IsDirective: true}
+	if newVersion == "" {
+		return e.log(WarnLevel, "%s: %s is no longer supported", e.Dep, e.Current)
+	}
+	return e.log(WarnLevel, "%s: %s is no longer supported, must be updated to %s", e.Dep, e.Current, newVersion)
+}
+
+func (e *Entry) log(level Level, format string, a ...interface{}) *Entry {
+	e.Kind = level
+	e.Message = format
+	e.Data = a
+	return e
 }
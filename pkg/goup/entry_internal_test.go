@@ -7,10 +7,12 @@ package goup
 import (
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/matryer/is"
 	"github.com/rvflash/goup/internal/errors"
 	"github.com/rvflash/goup/internal/semver"
+	"github.com/rvflash/goup/internal/sum"
 	"github.com/rvflash/goup/pkg/mod"
 	mockMod "github.com/rvflash/goup/testdata/mock/mod"
 
@@ -21,9 +23,12 @@ const (
 	v0       = "v0.0.0"
 	v1       = "v0.0.1"
 	repoName = "example.com/group/go"
+	pseudo   = "v0.0.1-0.20200121190230-accd165b1659"
 	oneTime  = 1
 )
 
+var pseudoTime = time.Date(2020, 1, 21, 19, 2, 30, 0, time.UTC)
+
 func TestNewEntry(t *testing.T) {
 	t.Parallel()
 	defer func() {
@@ -33,9 +38,18 @@ func TestNewEntry(t *testing.T) {
 	}()
 	var e *Entry
 	e.Args()
+	e.Err()
+	e.File()
 	e.Format()
 	e.Level()
+	e.Module()
+	e.NewPath()
+	e.NewVersion()
 	e.OutDated()
+	e.Path()
+	e.Status()
+	e.UpdateKind()
+	e.Version()
 }
 
 func TestNewCheck(t *testing.T) {
@@ -53,6 +67,9 @@ func TestNewCheck(t *testing.T) {
 	are.Equal(msg.Level(), DebugLevel)                        // mismatch level
 	are.True(strings.Contains(msg.Format(), "is up to date")) // mismatch message
 	are.Equal(len(msg.Args()), 2)                             // expected dep and version
+	are.Equal(msg.Status(), UpToDate)                         // mismatch status
+	are.Equal(msg.Path(), repoName)                           // mismatch path
+	are.Equal(msg.Version(), v0)                              // mismatch version
 	_, ok := msg.OutDated()
 	are.True(!ok) // not outdated
 }
@@ -127,6 +144,7 @@ func TestNewFailure(t *testing.T) {
 		t.Run(name, func(_ *testing.T) {
 			msg := newFailure(tt.err, tt.dep)
 			are.Equal(msg.Level(), ErrorLevel)               // mismatch level
+			are.Equal(msg.Status(), Failed)                  // mismatch status
 			are.True(strings.Contains(msg.Format(), tt.msg)) // mismatch message
 			are.Equal(len(msg.Args()), tt.len)               // mismatch len
 			_, ok := msg.OutDated()
@@ -144,16 +162,34 @@ func TestNewSkip(t *testing.T) {
 	)
 	defer ctrl.Finish()
 
-	are.Equal(newSkip(dep), nil) // mismatch default
+	are.Equal(newSkip(dep, "indirect"), nil) // mismatch default
 	dep = newDep(ctrl)
-	msg := newSkip(dep)
+	msg := newSkip(dep, "indirect")
 	are.Equal(msg.Level(), DebugLevel)                         // mismatch level
 	are.True(strings.Contains(msg.Format(), "update skipped")) // mismatch message
 	are.Equal(len(msg.Args()), 2)                              // expected dep and version
+	are.Equal(msg.Status(), Skipped)                           // mismatch status
 	_, ok := msg.OutDated()
 	are.True(!ok) // not outdated
 }
 
+func TestNewAbort(t *testing.T) {
+	t.Parallel()
+	var (
+		dep  mod.Module
+		are  = is.New(t)
+		ctrl = gomock.NewController(t)
+	)
+	defer ctrl.Finish()
+
+	are.Equal(newAbort(dep), nil) // mismatch default
+	dep = newDep(ctrl)
+	msg := newAbort(dep)
+	are.Equal(msg.Level(), DebugLevel)                        // mismatch level
+	are.True(strings.Contains(msg.Format(), "check aborted")) // mismatch message
+	are.Equal(msg.Status(), Aborted)                          // mismatch status
+}
+
 func TestNewUpdate(t *testing.T) {
 	t.Parallel()
 	var (
@@ -169,6 +205,8 @@ func TestNewUpdate(t *testing.T) {
 	are.Equal(msg.Level(), InfoLevel)                           // mismatch level
 	are.True(strings.Contains(msg.Format(), "will be updated")) // mismatch message
 	are.Equal(len(msg.Args()), 3)                               // expected dep, old and new versions
+	are.Equal(msg.Status(), Updated)                            // mismatch status
+	are.Equal(msg.NewVersion(), v1)                             // mismatch new version
 	_, ok := msg.OutDated()
 	are.True(!ok) // not outdated
 }
@@ -189,20 +227,254 @@ func TestNewOutOfDate(t *testing.T) {
 	are.Equal(msg.Level(), WarnLevel)                           // mismatch level
 	are.True(strings.Contains(msg.Format(), "must be updated")) // mismatch message
 	are.Equal(len(msg.Args()), 3)                               // expected dep, old and new versions
+	are.Equal(msg.Status(), Outdated)                           // mismatch status
+	are.Equal(msg.NewVersion(), v1)                             // mismatch new version
 	v, ok := msg.OutDated()
 	are.True(ok)     // outdated
 	are.Equal(v, v1) // new version mismatch
 }
 
+func TestNewDeprecated(t *testing.T) {
+	t.Parallel()
+	var (
+		are  = is.New(t)
+		ctrl = gomock.NewController(t)
+		up   = &mod.Upstream{Deprecated: "use example.com/go"}
+	)
+	defer ctrl.Finish()
+
+	are.Equal(newDeprecated(nil, up, WarnLevel), nil)                                // mismatch default
+	are.Equal(newDeprecated(&mockMod.MockModule{}, nil, WarnLevel), nil)             // mismatch without go.mod
+	are.Equal(newDeprecated(&mockMod.MockModule{}, &mod.Upstream{}, WarnLevel), nil) // mismatch not deprecated
+	msg := newDeprecated(newDep(ctrl), up, InfoLevel)
+	are.Equal(msg.Level(), InfoLevel)                                       // mismatch level
+	are.Equal(msg.Format(), "%s: module is deprecated: use example.com/go") // mismatch message
+	are.Equal(msg.Status(), Deprecated)                                     // mismatch status
+	_, ok := msg.OutDated()
+	are.True(!ok) // not outdated
+}
+
+func TestNewRetracted(t *testing.T) {
+	t.Parallel()
+	var (
+		are  = is.New(t)
+		ctrl = gomock.NewController(t)
+	)
+	defer ctrl.Finish()
+
+	are.Equal(newRetracted(nil, v1, ""), nil) // mismatch default
+	msg := newRetracted(newDep(ctrl), "", "100% broken")
+	are.Equal(msg.Level(), WarnLevel)                            // mismatch level
+	are.Equal(msg.Format(), "%s: %s is retracted: 100%% broken") // mismatch message
+	are.Equal(msg.Status(), Retracted)                           // mismatch status
+	_, ok := msg.OutDated()
+	are.True(!ok) // without new version
+	msg = newRetracted(newDep(ctrl), v1, "")
+	are.True(strings.Contains(msg.Format(), "must be updated")) // mismatch message
+	are.Equal(msg.NewVersion(), v1)                             // mismatch new version
+	v, ok := msg.OutDated()
+	are.True(ok)     // outdated
+	are.Equal(v, v1) // new version mismatch
+}
+
+func TestNewMajorAvailable(t *testing.T) {
+	t.Parallel()
+	var (
+		are  = is.New(t)
+		ctrl = gomock.NewController(t)
+	)
+	defer ctrl.Finish()
+
+	are.Equal(newMajorAvailable(nil, repoName+"/v2", "v2.1.0"), nil)       // mismatch default
+	are.Equal(newMajorAvailable(&mockMod.MockModule{}, "", "v2.1.0"), nil) // mismatch without path
+	msg := newMajorAvailable(newDep(ctrl), repoName+"/v2", "v2.1.0")
+	are.Equal(msg.Level(), WarnLevel)                         // mismatch level
+	are.Equal(msg.Format(), "%s: %s, major available: %s %s") // mismatch message
+	are.Equal(msg.Status(), MajorAvailable)                   // mismatch status
+	are.Equal(msg.NewPath(), repoName+"/v2")                  // mismatch new path
+	are.Equal(msg.NewVersion(), "v2.1.0")                     // mismatch new version
+	are.Equal(msg.UpdateKind(), MajorUpdate)                  // mismatch update kind
+	_, ok := msg.OutDated()
+	are.True(!ok) // not outdated in place
+}
+
+func TestNewMajorUpdate(t *testing.T) {
+	t.Parallel()
+	var (
+		are  = is.New(t)
+		ctrl = gomock.NewController(t)
+	)
+	defer ctrl.Finish()
+
+	are.Equal(newMajorUpdate(nil, repoName+"/v2", "v2.1.0"), nil)       // mismatch default
+	are.Equal(newMajorUpdate(&mockMod.MockModule{}, "", "v2.1.0"), nil) // mismatch without path
+	msg := newMajorUpdate(newDep(ctrl), repoName+"/v2", "v2.1.0")
+	are.Equal(msg.Level(), InfoLevel)                          // mismatch level
+	are.Equal(msg.Format(), "%s: %s will be updated to %s %s") // mismatch message
+	are.Equal(msg.Status(), Updated)                           // mismatch status
+	are.Equal(msg.NewPath(), repoName+"/v2")                   // mismatch new path
+}
+
+func TestNewRewrite(t *testing.T) {
+	t.Parallel()
+	are := is.New(t)
+	are.Equal(newRewrite("", repoName+"/v2", nil, true), nil) // mismatch default
+	msg := newRewrite(repoName, repoName+"/v2", []string{"a.go", "b.go"}, true)
+	are.Equal(msg.Level(), InfoLevel)                                                   // mismatch level
+	are.Equal(msg.Path(), "")                                                           // not a dependency
+	are.Equal(msg.Args(), []interface{}{repoName, repoName + "/v2", "2", "a.go, b.go"}) // mismatch args
+	msg = newRewrite(repoName, repoName+"/v2", nil, false)
+	are.True(strings.HasPrefix(msg.Format(), "%s: imports to rewrite")) // mismatch message
+}
+
+func TestNewSumFailure(t *testing.T) {
+	t.Parallel()
+	are := is.New(t)
+	are.Equal(newSumFailure(nil, repoName, v1), nil) // mismatch default
+	msg := newSumFailure(errors.ErrNotFound, repoName, v1)
+	are.Equal(msg.Level(), WarnLevel) // mismatch level
+	are.Equal(msg.Path(), "")         // not a dependency
+	msg = newSumFailure(errors.ErrChecksum, repoName, v1)
+	are.Equal(msg.Level(), ErrorLevel) // a hash unknown by the checksum database is an error
+}
+
+func TestNewUnverified(t *testing.T) {
+	t.Parallel()
+	var (
+		are  = is.New(t)
+		ctrl = gomock.NewController(t)
+		dep  = newPathModule(ctrl, repoName, v0)
+	)
+	defer ctrl.Finish()
+	are.Equal(newUnverified(nil, dep, repoName, v1), nil) // mismatch default
+	msg := newUnverified(errors.ErrChecksum, dep, repoName, v1)
+	are.Equal(msg.Level(), ErrorLevel)       // mismatch level
+	are.Equal(msg.Status(), Failed)          // mismatch status
+	are.Equal(msg.NewVersion(), "")          // the version must not be advised
+	are.Equal(msg.Err(), errors.ErrChecksum) // mismatch error
+}
+
+func TestNewSumUpdate(t *testing.T) {
+	t.Parallel()
+	var (
+		are  = is.New(t)
+		ctrl = gomock.NewController(t)
+		f    = mockMod.NewMockMod(ctrl)
+	)
+	defer ctrl.Finish()
+	are.Equal(newSumUpdate(nil, 1, 0), nil) // mismatch default
+	f.EXPECT().Module().Return(repoName).Times(oneTime)
+	msg := newSumUpdate(f, 2, 1)
+	are.Equal(msg.Level(), InfoLevel)                                      // mismatch level
+	are.Equal(msg.Args(), []interface{}{repoName, sum.Filename, "2", "1"}) // mismatch args
+}
+
+func TestNewPseudo(t *testing.T) {
+	t.Parallel()
+	var (
+		are  = is.New(t)
+		ctrl = gomock.NewController(t)
+		now  = pseudoTime.AddDate(0, 0, 3)
+	)
+	defer ctrl.Finish()
+
+	pv, _ := semver.ParsePseudo(semver.New(pseudo))
+	are.Equal(newPseudo(nil, pv, now), nil) // mismatch default
+	msg := newPseudo(newPseudoDep(ctrl, pseudo), pv, now)
+	are.Equal(msg.Level(), InfoLevel)                                                     // mismatch level
+	are.Equal(msg.Format(), "%s: %s is a pseudo-version of the commit %s, %s day(s) old") // mismatch message
+	are.Equal(msg.Args()[2:], []interface{}{"accd165b1659", "3"})                         // mismatch commit and age
+	are.Equal(msg.Status(), UpToDate)                                                     // mismatch status
+	_, ok := msg.OutDated()
+	are.True(!ok) // not outdated
+}
+
+func TestNewPseudoOutOfDate(t *testing.T) {
+	t.Parallel()
+	var (
+		are  = is.New(t)
+		ctrl = gomock.NewController(t)
+		now  = pseudoTime.AddDate(0, 0, 3)
+		next = "v0.2.1-0.20200201000000-bccd165b1659"
+	)
+	defer ctrl.Finish()
+
+	pv, _ := semver.ParsePseudo(semver.New(pseudo))
+	are.Equal(newPseudoOutOfDate(nil, pv, now, v1), nil) // mismatch default
+	msg := newPseudoOutOfDate(newPseudoDep(ctrl, pseudo), pv, now, "v0.3.0")
+	are.Equal(msg.Level(), WarnLevel)                                              // mismatch level
+	are.True(strings.HasSuffix(msg.Format(), "must be updated to the release %s")) // mismatch message
+	are.Equal(msg.Status(), Outdated)                                              // mismatch status
+	are.Equal(msg.UpdateKind(), MinorUpdate)                                       // mismatch update kind
+	v, ok := msg.OutDated()
+	are.True(ok)           // outdated
+	are.Equal(v, "v0.3.0") // new version mismatch
+	msg = newPseudoOutOfDate(newPseudoDep(ctrl, pseudo), pv, now, next)
+	are.True(strings.HasSuffix(msg.Format(), "must be updated to the last commit %s")) // mismatch message
+	are.Equal(msg.UpdateKind(), PseudoUpdate)                                          // mismatch update kind
+	v, ok = msg.OutDated()
+	are.True(ok)       // outdated
+	are.Equal(v, next) // new version mismatch
+}
+
 func newMod(ctrl *gomock.Controller) *mockMod.MockMod {
 	m := mockMod.NewMockMod(ctrl)
 	m.EXPECT().Module().Return(repoName).Times(oneTime)
 	return m
 }
 
+func newPseudoDep(ctrl *gomock.Controller, v string) *mockMod.MockModule {
+	d := mockMod.NewMockModule(ctrl)
+	d.EXPECT().Path().Return(repoName).AnyTimes()
+	d.EXPECT().Version().Return(semver.New(v)).AnyTimes()
+	return d
+}
+
 func newDep(ctrl *gomock.Controller) *mockMod.MockModule {
 	d := mockMod.NewMockModule(ctrl)
 	d.EXPECT().Path().Return(repoName).Times(oneTime)
 	d.EXPECT().Version().Return(semver.New(v0)).AnyTimes()
 	return d
 }
+
+func TestNewUnsupported(t *testing.T) {
+	t.Parallel()
+	are := is.New(t)
+	msg := newUnsupported("1.19", "")
+	are.Equal(msg.Level(), WarnLevel)                        // mismatch level
+	are.Equal(msg.Path(), goDirective)                       // mismatch path
+	are.True(msg.Directive())                                // mismatch kind
+	are.Equal(msg.Status(), Outdated)                        // mismatch status
+	are.Equal(msg.Format(), "%s: %s is no longer supported") // mismatch message
+	_, ok := msg.OutDated()
+	are.True(!ok) // without new version
+	msg = newUnsupported("1.19", "1.21.5")
+	v, ok := msg.OutDated()
+	are.True(ok)           // outdated
+	are.Equal(v, "1.21.5") // new version mismatch
+}
+
+func TestNewDirectiveOutOfDate(t *testing.T) {
+	t.Parallel()
+	are := is.New(t)
+	msg := newDirectiveOutOfDate(toolchainDirective, "go1.21.1", "go1.21.5")
+	are.Equal(msg.Path(), toolchainDirective) // mismatch path
+	are.True(msg.Directive())                 // mismatch kind
+	are.Equal(msg.Version(), "go1.21.1")      // mismatch version
+	v, ok := msg.OutDated()
+	are.True(ok)             // outdated
+	are.Equal(v, "go1.21.5") // new version mismatch
+}
+
+func TestNewDrift(t *testing.T) {
+	t.Parallel()
+	are := is.New(t)
+	are.Equal(newDrift(repoName, nil), nil) // mismatch default
+	msg := newDrift(repoName, map[string][]string{v1: {"b", "a"}, v0: {"c"}})
+	are.Equal(msg.Level(), WarnLevel)                                                          // mismatch level
+	are.Equal(msg.Status(), Drifted)                                                           // mismatch status
+	are.Equal(msg.Version(), v0)                                                               // mismatch version
+	are.Equal(msg.Format(), "%s: required at different versions: v0.0.0 by c; v0.0.1 by a, b") // mismatch message
+	_, ok := msg.OutDated()
+	are.True(!ok) // not outdated
+}
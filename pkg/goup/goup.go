@@ -10,32 +10,125 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
 	"sync/atomic"
 	"time"
 
 	errs "github.com/rvflash/goup/internal/errors"
+	"github.com/rvflash/goup/internal/imports"
 	"github.com/rvflash/goup/internal/path"
+	"github.com/rvflash/goup/internal/release"
 	"github.com/rvflash/goup/internal/semver"
+	"github.com/rvflash/goup/internal/sum"
 	"github.com/rvflash/goup/internal/vcs"
-	"github.com/rvflash/goup/internal/vcs/git"
-	"github.com/rvflash/goup/internal/vcs/goget"
 	"github.com/rvflash/goup/pkg/mod"
 	"github.com/rvflash/workr"
+
+	"golang.org/x/mod/module"
+)
+
+// List of update modes.
+const (
+	PatchMode = "patch"
+	MinorMode = "minor"
+	MajorMode = "major"
+)
+
+// List of levels of the deprecation notices.
+const (
+	DeprecatedError = "error"
+	DeprecatedWarn  = "warn"
+	DeprecatedInfo  = "info"
 )
 
 // Config is used as the settings of the GoUp application.
 type Config struct {
+	CheckHead        bool
+	Diff             bool
 	ExcludeIndirect  bool
 	ForceUpdate      bool
 	Major            bool
 	MajorMinor       bool
+	PrintConfig      bool
 	PrintVersion     bool
 	Strict           bool
+	UpdateDirectives bool
 	Verbose          bool
+	VerifySum        bool
+	Align            string
+	Format           string
+	HostPatterns     string
 	InsecurePatterns string
+	NoProxyPatterns  string
+	NoSumDBPatterns  string
 	OnlyReleases     string
+	ProxyURLs        string
+	Rewrite          string
+	SumDB            string
+	Jobs             int
+	MaxRequests      int
+	RateLimit        float64
+	Retries          int
 	Timeout          time.Duration
+	DepTimeout       time.Duration
 	BasicAuth        vcs.BasicAuthentifier
+	CacheDir         string
+	CacheTTL         time.Duration
+	Deprecated       string
+	GoReleases       string
+	Modules          []ModuleConfig
+
+	// run shares the remote properties between the checks of the go.mod files.
+	run *resolver
+}
+
+// ModuleConfig overrides the settings for the modules matching the glob pattern of its path.
+type ModuleConfig struct {
+	// Path is a glob pattern matching the module paths.
+	Path string
+	// Mode is the update mode: patch, minor or major. If empty, the global one is used.
+	Mode string
+	// Ignore skips the check of the modules.
+	Ignore bool
+	// OnlyReleases forces the modules to use a release tag.
+	OnlyReleases bool
+	// Versions restricts the allowed versions, like ">=v1.2.0, <v2".
+	Versions string
+}
+
+// module returns the settings to apply on this module path.
+// The first module configuration matching the path wins.
+// Without specific configuration, the global one is used.
+func (c Config) module(modulePath string) ModuleConfig {
+	m := ModuleConfig{Path: modulePath, Mode: c.mode()}
+	for _, o := range c.Modules {
+		if !path.Match(o.Path, modulePath) {
+			continue
+		}
+		if o.Mode != "" {
+			m.Mode = o.Mode
+		}
+		m.Ignore = o.Ignore
+		m.OnlyReleases = o.OnlyReleases
+		m.Versions = o.Versions
+		break
+	}
+	return m
+}
+
+// mode returns the global update mode.
+func (c Config) mode() string {
+	switch {
+	case c.Major:
+		return MajorMode
+	case c.MajorMinor:
+		return MinorMode
+	default:
+		return PatchMode
+	}
 }
 
 // Checker must be implemented to checkFile updates on go.mod file or module.
@@ -45,7 +138,53 @@ type Checker func(ctx context.Context, file mod.Mod, conf Config) chan Message
 func Check(ctx context.Context, file mod.Mod, conf Config) chan Message {
 	chk := newGoUp(conf)
 	go chk.checkFile(ctx, file)
-	return chk.log
+	return locate(chk.log, file)
+}
+
+// locate defines the go.mod file of the messages, as they are sent.
+func locate(in chan Message, file mod.Mod) chan Message {
+	if file == nil {
+		return in
+	}
+	out := make(chan Message)
+	go func() {
+		defer close(out)
+		for msg := range in {
+			if e, ok := msg.(*Entry); ok && e != nil {
+				e.FileName, e.ModPath = file.Name(), file.Module()
+			}
+			out <- msg
+		}
+	}()
+	return out
+}
+
+// UpdateSum keeps the go.sum file next to the go.mod file consistent with the updates applied on it,
+// like those of an alignment, as the check does with its own updates. The versions already hashed are kept.
+func UpdateSum(ctx context.Context, file mod.Mod, conf Config) chan Message {
+	u := newGoUp(conf)
+	go func() {
+		defer close(u.log)
+		if !u.ready(ctx) || file == nil {
+			u.log <- newError(errs.ErrMod, file)
+			return
+		}
+		ctx, cancel := context.WithTimeout(ctx, u.Timeout)
+		defer cancel()
+		u.updateSum(ctx, file)
+	}()
+	return locate(u.log, file)
+}
+
+// NewChecker returns a checker sharing the remote properties between the go.mod files it checks.
+// So a dependency used by several modules, like the members of a workspace, is only fetched once,
+// even if these files are checked concurrently.
+func NewChecker() Checker {
+	run := newResolver()
+	return func(ctx context.Context, file mod.Mod, conf Config) chan Message {
+		conf.run = run
+		return Check(ctx, file, conf)
+	}
 }
 
 // newGoUp returns a new instance of GoUp with the default dependencies checkers.
@@ -54,13 +193,17 @@ func newGoUp(conf Config, sets ...setter) *goUp {
 		u = &goUp{
 			Config: conf,
 			log:    make(chan Message),
+			now:    time.Now,
 		}
-		httpClient = vcs.NewHTTPClient(conf.Timeout, conf.InsecurePatterns)
-		gitVCS     = git.New(httpClient, conf.BasicAuth)
+		s = conf.run.get(conf)
 	)
+	u.cacheErr = s.cacheErr
 	sets = append([]setter{
-		setGit(gitVCS),
-		setGoGet(goget.New(httpClient, gitVCS)),
+		setReleases(s.releases),
+		setGit(s.git),
+		setGoGet(s.goGet),
+		setGoProxy(s.goProxy),
+		setSumDB(s.sumDB, s.sumErr),
 	}, sets...)
 	for _, set := range sets {
 		set(u)
@@ -70,8 +213,21 @@ func newGoUp(conf Config, sets ...setter) *goUp {
 
 type goUp struct {
 	Config
-	git, goGet vcs.System
-	log        chan Message
+	git, goGet, goProxy vcs.System
+	releases            func(ctx context.Context) (release.Releases, error)
+	log                 chan Message
+	now                 func() time.Time
+	cacheErr            error
+	sumDB               *sum.DB
+	sumErr              error
+
+	mu    sync.Mutex
+	moves []move
+}
+
+// move is a requirement moved to the module path of a new major version.
+type move struct {
+	oldPath, newPath string
 }
 
 const (
@@ -88,32 +244,250 @@ func (e *goUp) checkFile(parent context.Context, file mod.Mod) {
 		e.log <- newError(errs.ErrMod, file)
 		return
 	}
+	if e.cacheErr != nil {
+		// Without cache, the check goes on with the remotes.
+		e.log <- NewEntry(DebugLevel, "cache disabled: %s", e.cacheErr)
+	}
+	if e.VerifySum {
+		if e.sumErr != nil {
+			// The versions can not be advised without verification.
+			e.log <- newError(e.sumErr, file)
+			return
+		}
+		if e.sumDB == nil {
+			e.log <- NewEntry(DebugLevel, "checksum verification disabled by GOSUMDB=%s", sum.Off)
+		}
+	}
 	ctx, cancel := context.WithTimeout(parent, e.Timeout)
 	defer cancel()
 	bad := e.checkDependencies(ctx, file)
-	if !e.ForceUpdate {
+	bad += e.checkDirectives(ctx, file)
+	if !e.update() {
 		return
 	}
 	if bad > 0 {
 		e.log <- newError(errs.ErrNotModified, file)
 		return
 	}
+	if e.Diff {
+		// The updates are only applied in memory, the caller shows them.
+		e.rewriteImports(file, false)
+		return
+	}
 	if err := updateFile(file); err != nil {
 		e.log <- newError(err, file)
+		return
+	}
+	e.updateSum(ctx, file)
+	e.rewriteImports(file, true)
+}
+
+// updateSum keeps the go.sum file next to the go.mod file consistent with its updates.
+// The hashes of the zip archive and of the go.mod file of the new versions are computed on the files
+// downloaded from the Go module proxy, those of the zip archive of the versions no longer used are removed.
+// The hashes of their go.mod file are kept, as the module graph may still need them.
+// A module without go.sum file is left to the go command.
+func (e *goUp) updateSum(ctx context.Context, file mod.Mod) {
+	added, removed := file.Changes()
+	if len(added) == 0 && len(removed) == 0 {
+		return
+	}
+	name := filepath.Join(filepath.Dir(file.Name()), sum.Filename)
+	if _, err := os.Stat(name); err != nil {
+		return
+	}
+	f, err := sum.Read(name)
+	if err != nil {
+		e.log <- newError(err, file)
+		return
+	}
+	// n and r count the lines added and removed.
+	var n, r int
+	for _, v := range removed {
+		if h := f.Hash(v.Path, v.Version); len(h) > 0 {
+			f.Remove(v.Path, v.Version)
+			r += len(h)
+		}
+	}
+	for _, v := range added {
+		if len(f.Hash(v.Path, v.Version)) > 0 && len(f.Hash(v.Path, v.Version+sum.ModSuffix)) > 0 {
+			// Already hashed, like the versions of the check before an alignment.
+			continue
+		}
+		reqs, err := e.addSum(ctx, f, v)
+		if err != nil {
+			e.log <- newSumFailure(err, v.Path, v.Version)
+			continue
+		}
+		n += 2
+		// The go command also reads the go.mod files of their requirements to load the module graph.
+		for _, req := range reqs {
+			if len(f.Hash(req.Path, req.Version+sum.ModSuffix)) > 0 {
+				continue
+			}
+			if err = e.addModSum(ctx, f, req); err != nil {
+				e.log <- newSumFailure(err, req.Path, req.Version+sum.ModSuffix)
+				continue
+			}
+			n++
+		}
+	}
+	if n == 0 && r == 0 {
+		return
+	}
+	if err = os.WriteFile(name, f.Format(), perm); err != nil {
+		e.log <- newError(err, file)
+		return
+	}
+	e.log <- newSumUpdate(file, n, r)
+}
+
+// addSum adds the hashes of this module version to the go.sum file and returns the requirements of its go.mod file.
+func (e *goUp) addSum(ctx context.Context, f *sum.File, v module.Version) ([]module.Version, error) {
+	zf, ok := e.goProxy.(vcs.ZipFetcher)
+	if !ok {
+		return nil, errs.ErrSystem
+	}
+	b, modHash, err := e.modSum(ctx, v)
+	if err != nil {
+		return nil, err
+	}
+	z, err := zf.FetchZip(ctx, v.Path, v.Version)
+	if err != nil {
+		return nil, err
+	}
+	zipHash, err := sum.HashZip(z)
+	if err != nil {
+		return nil, err
+	}
+	if e.sumDB.CanVerify(v.Path) {
+		if err = e.sumDB.Verify(ctx, v.Path, v.Version, zipHash); err != nil {
+			return nil, err
+		}
+	}
+	if err = f.Add(v.Path, v.Version+sum.ModSuffix, modHash); err != nil {
+		return nil, err
+	}
+	if err = f.Add(v.Path, v.Version, zipHash); err != nil {
+		return nil, err
+	}
+	var reqs []module.Version
+	if up, err := mod.ParseUpstream(v.Path, b); err == nil {
+		// An unreadable go.mod file only leaves its requirements unknown.
+		reqs = up.Requires
+	}
+	return reqs, nil
+}
+
+// addModSum adds the hash of the go.mod file of this module version to the go.sum file.
+func (e *goUp) addModSum(ctx context.Context, f *sum.File, v module.Version) error {
+	_, h, err := e.modSum(ctx, v)
+	if err != nil {
+		return err
+	}
+	return f.Add(v.Path, v.Version+sum.ModSuffix, h)
+}
+
+// modSum returns the go.mod file of this module version and its hash, verified with the checksum database.
+func (e *goUp) modSum(ctx context.Context, v module.Version) ([]byte, string, error) {
+	mf, ok := e.goProxy.(vcs.ModFetcher)
+	if !ok || !e.goProxy.CanFetch(v.Path) {
+		return nil, "", errs.ErrSystem
+	}
+	b, err := mf.FetchMod(ctx, v.Path, v.Version)
+	if err != nil {
+		return nil, "", err
+	}
+	h, err := sum.HashMod(b)
+	if err != nil {
+		return nil, "", err
+	}
+	if e.sumDB.CanVerify(v.Path) {
+		// The hashes computed on the files of the proxy must be those of the checksum database.
+		if err = e.sumDB.Verify(ctx, v.Path, v.Version+sum.ModSuffix, h); err != nil {
+			return nil, "", err
+		}
+	}
+	return b, h, nil
+}
+
+// rewriteImports replaces the imports of the requirements moved to a new major path
+// in the Go files of the module, or with write disabled, only lists the files to rewrite.
+func (e *goUp) rewriteImports(file mod.Mod, write bool) {
+	if len(e.moves) == 0 {
+		return
+	}
+	dir := filepath.Dir(file.Name())
+	for _, m := range e.moves {
+		files, err := imports.Rewrite(dir, m.oldPath, m.newPath, write)
+		if err != nil {
+			e.log <- newError(err, file)
+			continue
+		}
+		e.log <- newRewrite(m.oldPath, m.newPath, files, write)
 	}
 }
 
 // Errors are internally managed with a dedicated channel.
 // So we only return each task as succeeded and eventually the number of fails.
+// In strict mode, the first error cancels the group and the checks not completed yet are aborted.
 func (e *goUp) checkDependencies(parent context.Context, file mod.Mod) uint64 {
 	grp, ctx := workr.WithContext(parent)
-	var bad uint64
+	var bad, done, aborted uint64
+	var stopped int32
+	abort := func(dep mod.Module) error {
+		atomic.AddUint64(&bad, delta)
+		atomic.AddUint64(&aborted, delta)
+		e.log <- newAbort(dep)
+		return nil
+	}
 	for _, d := range file.Dependencies() {
 		dep := d
 		grp.Go(func() (err error) {
-			log := e.checkDependency(ctx, dep)
+			if atomic.LoadInt32(&stopped) > 0 {
+				return abort(dep)
+			}
+			log, up, major := e.checkDependencyWithTimeout(ctx, dep)
+			if up == nil && log.Status() != Failed && log.Status() != Skipped {
+				e.log <- newUpstreamSkip(dep)
+			}
+			if d := newDeprecated(dep, up, e.deprecatedLevel()); d != nil {
+				if d.Level() == ErrorLevel {
+					atomic.AddUint64(&bad, delta)
+				}
+				e.log <- d
+			}
+			toMove := major != nil && major.NewPath() != "" && e.update() && e.rewrite(dep)
+			if major != nil && !toMove {
+				// A major version available is a notice, it must not prevent the updates of the file.
+				if major.Level() == ErrorLevel {
+					atomic.AddUint64(&bad, delta)
+				}
+				e.log <- major
+			}
+			if e.Strict && log.Level() == ErrorLevel {
+				if !atomic.CompareAndSwapInt32(&stopped, 0, 1) {
+					// Canceled by another check.
+					return abort(dep)
+				}
+				atomic.AddUint64(&bad, delta)
+				atomic.AddUint64(&done, delta)
+				e.log <- log
+				// Returning the error cancels the other checks.
+				return log.Err()
+			}
+			atomic.AddUint64(&done, delta)
+			if toMove {
+				// The requirement moves to the new major path, the update of the current one is useless.
+				if log.Level() == ErrorLevel {
+					atomic.AddUint64(&bad, delta)
+				}
+				e.log <- log
+				atomic.AddUint64(&bad, e.moveRequire(file, dep, major))
+				return nil
+			}
 			v, ok := log.OutDated()
-			if !ok || !e.ForceUpdate {
+			if !ok || !e.update() {
 				if log.Level() < InfoLevel {
 					atomic.AddUint64(&bad, delta)
 				}
@@ -135,40 +509,410 @@ func (e *goUp) checkDependencies(parent context.Context, file mod.Mod) uint64 {
 		})
 	}
 	_ = grp.Wait()
+	if atomic.LoadInt32(&stopped) > 0 {
+		e.log <- NewEntry(InfoLevel, "%s: strict mode: %s check(s) completed, %s aborted",
+			file.Module(), strconv.FormatUint(done, 10), strconv.FormatUint(aborted, 10))
+	}
 	return bad
 }
 
+// checkDirectives checks the go and toolchain directives of the go.mod file against the Go releases.
+// The go directive must target one of the supported releases, the toolchain the latest one allowed by the mode.
+// In patch mode, an unsupported go directive is advised to move to the oldest supported release.
+// Without the list of releases, the check is skipped.
+func (e *goUp) checkDirectives(ctx context.Context, file mod.Mod) (bad uint64) {
+	goVersion, toolchain := file.Go(), file.Toolchain()
+	if toolchain == release.Default {
+		toolchain = ""
+	}
+	if goVersion == "" && toolchain == "" {
+		return 0
+	}
+	rs, err := e.releases(ctx)
+	if err != nil {
+		e.log <- NewEntry(DebugLevel, "%s: directives check skipped: %s", file.Module(), err)
+		return 0
+	}
+	latest := func(v string) string {
+		var w string
+		if e.mode() == PatchMode {
+			w = rs.LatestPatch(v)
+		} else {
+			w = rs.Latest()
+		}
+		if w == "" || release.Compare(v, w) >= 0 {
+			return ""
+		}
+		return w
+	}
+	if goVersion != "" {
+		if rs.Supported(goVersion) {
+			e.log <- newDirectiveCheck(goDirective, goVersion)
+		} else {
+			v := latest(goVersion)
+			if e.mode() == PatchMode {
+				// The latest patch of an unsupported minor version is still unsupported.
+				v = rs.OldestSupported()
+			}
+			bad += e.updateDirective(file, newUnsupported(goVersion, v))
+		}
+	}
+	if toolchain != "" {
+		if v := latest(toolchain); v != "" {
+			bad += e.updateDirective(file, newDirectiveOutOfDate(toolchainDirective, toolchain, toolchainPrefix+v))
+		} else {
+			e.log <- newDirectiveCheck(toolchainDirective, toolchain)
+		}
+	}
+	return bad
+}
+
+// updateDirective applies the update advised by this entry, only if the update of the directives is asked,
+// as it changes the minimum Go version required by the module.
+// A directive to update is a warning: only a failed update is counted as bad.
+func (e *goUp) updateDirective(file mod.Mod, log *Entry) (bad uint64) {
+	v, ok := log.OutDated()
+	if !ok || !e.update() || !e.UpdateDirectives {
+		e.log <- log
+		return 0
+	}
+	var err error
+	if log.Path() == goDirective {
+		err = file.UpdateGo(v)
+	} else {
+		err = file.UpdateToolchain(v)
+	}
+	if err != nil {
+		e.log <- newDirectiveFailure(err, log.Path(), log.Version())
+		return delta
+	}
+	e.log <- newDirectiveUpdate(log.Path(), log.Version(), v)
+	return 0
+}
+
+// checkDependencyWithTimeout checks the given module within its own timeout, if any,
+// so a slow remote only fails its dependencies. The retried attempts are notified for debug.
+func (e *goUp) checkDependencyWithTimeout(parent context.Context, dep mod.Module) (*Entry, *mod.Upstream, *Entry) {
+	ctx, cancel := parent, context.CancelFunc(func() {})
+	if e.DepTimeout > 0 {
+		ctx, cancel = context.WithTimeout(parent, e.DepTimeout)
+	}
+	defer cancel()
+	ctx = vcs.WithRetries(ctx)
+	log, up, major := e.checkDependency(ctx, dep)
+	if d := newRetries(dep, vcs.Retries(ctx)); d != nil {
+		e.log <- d
+	}
+	return e.verify(ctx, dep, log), up, e.verify(ctx, dep, major)
+}
+
+// verify checks that the checksum database knows the version proposed by this entry.
+// Otherwise, the version is not advised and the entry is replaced by a failure.
+// The module paths matching GONOSUMDB are not verified.
+func (e *goUp) verify(ctx context.Context, dep mod.Module, log *Entry) *Entry {
+	if e.sumDB == nil || log == nil || log.NewVersion() == "" {
+		return log
+	}
+	p := log.NewPath()
+	if p == "" {
+		p = dep.Path()
+	}
+	if !e.sumDB.CanVerify(p) {
+		return log
+	}
+	if _, err := e.sumDB.Lookup(ctx, p, log.NewVersion()); err != nil {
+		return newUnverified(err, dep, p, log.NewVersion())
+	}
+	return log
+}
+
 // checkDependency checks the version of the given module based on this configuration.
-func (e *goUp) checkDependency(ctx context.Context, dep mod.Module) *Entry {
+// It also returns the go.mod file published by the module, if it has been read, and in major mode
+// or if the module is to rewrite, the notice of a newer major version published under another module path, if any.
+func (e *goUp) checkDependency(ctx context.Context, dep mod.Module) (*Entry, *mod.Upstream, *Entry) {
+	conf := e.module(dep.Path())
+	if conf.Ignore {
+		return newSkip(dep, "ignored"), nil, nil
+	}
 	if e.ExcludeIndirect && dep.Indirect() {
-		return newSkip(dep)
+		return newSkip(dep, "indirect"), nil, nil
 	}
-	for _, system := range []vcs.System{e.goGet, e.git} {
+	allowed, err := semver.ParseRange(conf.Versions)
+	if err != nil {
+		return newFailure(err, dep), nil, nil
+	}
+	for _, system := range []vcs.System{e.goProxy, e.goGet, e.git} {
 		if !system.CanFetch(dep.Path()) {
 			continue
 		}
-		vs, err := system.FetchPath(ctx, dep.Path())
+		all, err := system.FetchPath(ctx, dep.Path())
+		if errors.Is(err, errs.ErrDirect) {
+			// The proxy list allows to fall back on the next VCS.
+			continue
+		}
 		if err != nil {
-			return newFailure(err, dep)
+			return newFailure(err, dep), nil, nil
 		}
-		x := dep.ExcludeVersions()
-		if len(x) > 0 {
-			vs = vs.Not(stringer(x)...)
+		var major *Entry
+		if conf.Mode == MajorMode || e.rewrite(dep) {
+			major = e.checkMajor(ctx, system, dep, all, allowed)
 		}
-		v, ok := latest(vs, dep, e.Config.Major, e.Config.MajorMinor)
-		if !ok {
-			return newCheck(dep)
+		// Only the versions of the major of the module path can be required with this path.
+		vs := inPath(all, dep)
+		up := e.upstream(ctx, system, dep, vs)
+		return e.checkVersions(ctx, dep, conf, allowed, up, vs), up, major
+	}
+	return newFailure(errs.ErrSystem, dep), nil, nil
+}
+
+// checkVersions checks the version of the given module against the versions published under its path.
+func (e *goUp) checkVersions(
+	ctx context.Context, dep mod.Module, conf ModuleConfig, allowed semver.Range, up *mod.Upstream, vs semver.Tags,
+) *Entry {
+	x := dep.ExcludeVersions()
+	if len(x) > 0 {
+		vs = vs.Not(stringer(x)...)
+	}
+	vs = allowed.Filter(up.Allowed(vs))
+	r, retracted := up.Retracted(dep.Version())
+	v, ok := latest(vs, dep, conf.Mode == MajorMode, conf.Mode == MinorMode)
+	if ok && semver.Compare(dep.Version(), v) < 0 {
+		if retracted {
+			return newRetracted(dep, semver.Base(v), r.Rationale)
 		}
-		if semver.Compare(dep.Version(), v) < 0 {
-			return newOutOfDate(dep, v.String())
+		if pv, isPseudo := semver.ParsePseudo(dep.Version()); isPseudo {
+			return newPseudoOutOfDate(dep, pv, e.now(), semver.Base(v))
 		}
-		err = onlyTag(dep, e.OnlyReleases)
-		if err != nil {
-			return newFailure(err, dep)
+		return newOutOfDate(dep, semver.Base(v))
+	}
+	if retracted {
+		return newRetracted(dep, "", r.Rationale)
+	}
+	if !ok {
+		return e.checkPseudo(ctx, dep, vs)
+	}
+	err := onlyTag(dep, e.OnlyReleases)
+	if err == nil && conf.OnlyReleases && !dep.Version().IsTag() {
+		err = errs.ErrExpectedTag
+	}
+	if err != nil {
+		return newFailure(err, dep)
+	}
+	return e.checkPseudo(ctx, dep, vs)
+}
+
+// maxMajors is the maximum number of next major module paths probed on a Go module proxy.
+const maxMajors = 5
+
+// checkMajor looks for the latest release of a newer major version of the module, published under another path,
+// like example.com/lib/v3 for example.com/lib/v2. A repository lists the tags of every major, whereas
+// a Go module proxy only lists the versions of the requested path, so the next major paths are probed one by one.
+// The major versions published without go.mod file, as +incompatible, are listed under the path without suffix,
+// so they are not concerned. The gopkg.in paths suffix every major, v0 and v1 included, with a dot, like gopkg.in/yaml.v3.
+func (e *goUp) checkMajor(ctx context.Context, system vcs.System, dep mod.Module, all semver.Tags, allowed semver.Range) *Entry {
+	prefix, pathMajor, ok := module.SplitPathVersion(dep.Path())
+	if !ok {
+		return nil
+	}
+	sep := majorSep
+	current, _ := strconv.Atoi(strings.TrimPrefix(pathMajor, sep))
+	if _, major, ok := vcs.Gopkg(dep.Path()); ok {
+		sep = gopkgMajorSep
+		current, _ = strconv.Atoi(strings.TrimPrefix(major, "v"))
+	} else if pathMajor == "" {
+		// Without suffix, the path is the one of the v0 and v1 versions, or of the major required as +incompatible.
+		current = 1
+		if dep.Version().Build() == incompatibleBuild {
+			current = majorOf(dep.Version())
+		}
+	}
+	var (
+		newPath string
+		newest  semver.Tag
+	)
+	pick := func(n int, vs semver.Tags) {
+		for _, v := range vs {
+			if n > 0 && majorOf(v) != n {
+				continue
+			}
+			if !v.IsTag() || majorOf(v) <= current || !allowed.Match(v) {
+				continue
+			}
+			if newest == nil || semver.Compare(newest, v) < 0 {
+				newPath, newest = prefix+sep+strconv.Itoa(majorOf(v)), v
+			}
+		}
+	}
+	if system != e.goProxy {
+		pick(0, all)
+	} else {
+		for n := current + 1; n <= current+maxMajors; n++ {
+			vs, err := system.FetchPath(ctx, prefix+sep+strconv.Itoa(n))
+			if err != nil {
+				break
+			}
+			last := newest
+			pick(n, vs)
+			if newest == last {
+				break
+			}
+		}
+	}
+	if newest == nil {
+		return nil
+	}
+	return newMajorAvailable(dep, e.majorPath(ctx, system, newPath, newest), semver.Base(newest))
+}
+
+// List of separators between the module path and its major version.
+const (
+	majorSep      = "/v"
+	gopkgMajorSep = ".v"
+)
+
+// majorPath returns the module path declared by the go.mod file of this version of the new major path,
+// as the module may have migrated to another path, like gopkg.in/yaml.v3 to go.yaml.in/yaml/v3.
+// Without the capacity to read it, or if the declared path does not match the major version, the new path is kept.
+func (e *goUp) majorPath(ctx context.Context, system vcs.System, newPath string, v semver.Tag) string {
+	mf, ok := system.(vcs.ModFetcher)
+	if !ok {
+		return newPath
+	}
+	b, err := mf.FetchMod(ctx, newPath, semver.Base(v))
+	if err != nil {
+		return newPath
+	}
+	up, err := mod.ParseUpstream(newPath, b)
+	if err != nil || up.Path == "" {
+		return newPath
+	}
+	_, pathMajor, ok := module.SplitPathVersion(up.Path)
+	if !ok || module.CheckPathMajor(semver.Base(v), pathMajor) != nil {
+		return newPath
+	}
+	return up.Path
+}
+
+// inPath returns the versions that can be required with the module path of the dependency:
+// those of the major version of its path, v0 or v1 without suffix, v2 with /v2, etc.
+// A gopkg.in path only accepts the versions of its major, like v2 for gopkg.in/yaml.v2.
+// Without suffix, the +incompatible versions are also accepted, like those listed by a Go module proxy.
+// As the repositories list their tags without this build metadata, it is added to the tags of the major
+// of a dependency already required as +incompatible.
+func inPath(versions semver.Tags, dep mod.Module) semver.Tags {
+	_, pathMajor, ok := module.SplitPathVersion(dep.Path())
+	if !ok {
+		return versions
+	}
+	incompatible := dep.Version() != nil && dep.Version().Build() == incompatibleBuild
+	res := make(semver.Tags, 0, len(versions))
+	for _, v := range versions {
+		if incompatible && v.Build() == "" && v.Major() == dep.Version().Major() {
+			v = semver.New(v.String() + incompatibleBuild)
+		}
+		if module.CheckPathMajor(semver.Base(v), pathMajor) == nil {
+			res = append(res, v)
 		}
+	}
+	return res
+}
+
+const incompatibleBuild = "+incompatible"
+
+// majorOf returns the major version number of this version, 0 if it is invalid.
+func majorOf(v semver.Tag) int {
+	if v == nil {
+		return 0
+	}
+	n, _ := strconv.Atoi(strings.TrimPrefix(v.Major(), "v"))
+	return n
+}
+
+// checkPseudo checks the commit referenced by the pseudo-version of an up-to-date module, if any.
+// Its age is notified and, if enabled, the last commit of the default branch is fetched
+// to propose its pseudo-version when the branch has moved on.
+func (e *goUp) checkPseudo(ctx context.Context, dep mod.Module, versions semver.Tags) *Entry {
+	pv, ok := semver.ParsePseudo(dep.Version())
+	if !ok {
 		return newCheck(dep)
 	}
-	return newFailure(errs.ErrSystem, dep)
+	if !e.CheckHead {
+		return newPseudo(dep, pv, e.now())
+	}
+	head, err := e.fetchHead(ctx, dep.Path())
+	if err != nil {
+		return newFailure(err, dep)
+	}
+	if pv.IsCommit(head.Hash) || !head.Time.After(pv.Time) {
+		return newPseudo(dep, pv, e.now())
+	}
+	// The new pseudo-version is based on the last release of the major, or on the base of the current one.
+	major, older := dep.Version().Major(), pv.Base
+	if v := semver.LatestMinor(major, versions); v != nil {
+		older = semver.Base(v)
+	}
+	return newPseudoOutOfDate(dep, pv, e.now(), semver.NewPseudo(major, older, head.Time, head.Hash)+dep.Version().Build())
+}
+
+// fetchHead returns the last commit of the default branch of the repository of the module.
+// The Go module proxies do not expose the branches, so only the other systems are used.
+func (e *goUp) fetchHead(ctx context.Context, path string) (*vcs.Revision, error) {
+	for _, system := range []vcs.System{e.goGet, e.git} {
+		hf, ok := system.(vcs.HeadFetcher)
+		if !ok || !system.CanFetch(path) {
+			continue
+		}
+		rev, err := hf.FetchHead(ctx, path)
+		if errors.Is(err, errs.ErrDirect) {
+			continue
+		}
+		return rev, err
+	}
+	return nil, errs.ErrSystem
+}
+
+// upstream returns the go.mod file published by the dependency in its latest version, as listed by this system.
+// As with the go command, the latest release is preferred to the latest prerelease.
+// Only a Go module proxy serves it, so without proxy for the dependency, the check goes on
+// without taking care of the retracted versions and of the deprecation of the module.
+func (e *goUp) upstream(ctx context.Context, system vcs.System, dep mod.Module, versions semver.Tags) *mod.Upstream {
+	mf, ok := system.(vcs.ModFetcher)
+	if !ok {
+		return nil
+	}
+	var releases semver.Tags
+	for _, v := range versions {
+		if v.IsTag() {
+			releases = append(releases, v)
+		}
+	}
+	v := semver.Latest(releases)
+	if v == nil {
+		if v = semver.Latest(append(semver.Tags(nil), versions...)); v == nil {
+			return nil
+		}
+	}
+	b, err := mf.FetchMod(ctx, dep.Path(), semver.Base(v))
+	if err != nil {
+		return nil
+	}
+	up, err := mod.ParseUpstream(dep.Path(), b)
+	if err != nil {
+		return nil
+	}
+	return up
+}
+
+// deprecatedLevel returns the level of the deprecation notices, as warning by default.
+func (e *goUp) deprecatedLevel() Level {
+	switch e.Deprecated {
+	case DeprecatedError:
+		return ErrorLevel
+	case DeprecatedInfo:
+		return InfoLevel
+	default:
+		return WarnLevel
+	}
 }
 
 func stringer(list []semver.Tag) []fmt.Stringer {
@@ -190,16 +934,43 @@ func updateFile(file mod.Mod) error {
 	return os.WriteFile(file.Name(), buf, perm)
 }
 
+// moveRequire moves the requirement of this dependency to the module path of its newer major version.
+func (e *goUp) moveRequire(file mod.Mod, dep mod.Module, major Message) (bad uint64) {
+	if err := file.MoveRequire(dep.Path(), major.NewPath(), major.NewVersion()); err != nil {
+		e.log <- newFailure(err, dep)
+		return delta
+	}
+	e.mu.Lock()
+	e.moves = append(e.moves, move{oldPath: dep.Path(), newPath: major.NewPath()})
+	e.mu.Unlock()
+	e.log <- newMajorUpdate(dep, major.NewPath(), major.NewVersion())
+	return 0
+}
+
+// rewrite reports whether the requirement of this dependency must move to the path of its newer major version.
+// The replaced modules are never moved.
+func (e *goUp) rewrite(dep mod.Module) bool {
+	return path.Match(e.Rewrite, dep.Path()) && !dep.Replacement()
+}
+
+// update reports whether the update advices must be applied on the go.mod file.
+// In diff mode, they are applied without writing the file.
+func (e *goUp) update() bool {
+	return e.ForceUpdate || e.Diff
+}
+
 func (e *goUp) ready(ctx context.Context) bool {
-	return ctx != nil && e.log != nil && e.goGet != nil && e.git != nil
+	return ctx != nil && e.log != nil && e.releases != nil && e.goProxy != nil && e.goGet != nil && e.git != nil
 }
 
+// latest returns the latest version of the module allowed by the update mode.
+// A pseudo-version is not a release: any newer release of its major is proposed, even in patch mode.
 func latest(versions semver.Tags, dep mod.Module, major, majorMinor bool) (semver.Tag, bool) {
 	var v semver.Tag
 	switch {
 	case major:
 		v = semver.Latest(versions)
-	case majorMinor:
+	case majorMinor, isPseudo(dep):
 		v = semver.LatestMinor(dep.Version().Major(), versions)
 	default:
 		v = semver.LatestPatch(dep.Version().MajorMinor(), versions)
@@ -207,6 +978,11 @@ func latest(versions semver.Tags, dep mod.Module, major, majorMinor bool) (semve
 	return v, v != nil
 }
 
+func isPseudo(dep mod.Module) bool {
+	_, ok := semver.ParsePseudo(dep.Version())
+	return ok
+}
+
 func onlyTag(d mod.Module, globs string) error {
 	if path.Match(globs, d.Path()) && !d.Version().IsTag() {
 		return errs.ErrExpectedTag
@@ -217,6 +993,13 @@ func onlyTag(d mod.Module, globs string) error {
 // setter defines the interface used to set settings.
 type setter func(u *goUp)
 
+// setReleases sets the provider of the Go releases.
+func setReleases(fn func(ctx context.Context) (release.Releases, error)) setter {
+	return func(u *goUp) {
+		u.releases = fn
+	}
+}
+
 // setGit set the VCS git.
 func setGit(git vcs.System) setter {
 	return func(u *goUp) {
@@ -224,9 +1007,25 @@ func setGit(git vcs.System) setter {
 	}
 }
 
+// setGoProxy sets the VCS based on the Go module proxies.
+func setGoProxy(goProxy vcs.System) setter {
+	return func(u *goUp) {
+		u.goProxy = goProxy
+	}
+}
+
 // setGoGet sets the VCS go-get.
 func setGoGet(goGet vcs.System) setter {
 	return func(u *goUp) {
 		u.goGet = goGet
 	}
 }
+
+// setSumDB sets the checksum database, only used to verify the versions if requested.
+func setSumDB(db *sum.DB, err error) setter {
+	return func(u *goUp) {
+		if u.VerifySum {
+			u.sumDB, u.sumErr = db, err
+		}
+	}
+}
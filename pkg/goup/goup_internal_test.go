@@ -5,8 +5,13 @@
 package goup
 
 import (
+	"archive/zip"
+	"bytes"
 	"context"
+	"crypto/rand"
 	"errors"
+	"fmt"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"strings"
@@ -15,12 +20,18 @@ import (
 
 	"github.com/matryer/is"
 	errup "github.com/rvflash/goup/internal/errors"
+	"github.com/rvflash/goup/internal/release"
 	"github.com/rvflash/goup/internal/semver"
+	"github.com/rvflash/goup/internal/sum"
 	"github.com/rvflash/goup/internal/vcs"
 	"github.com/rvflash/goup/pkg/mod"
 	mockMod "github.com/rvflash/goup/testdata/mock/mod"
 	mockVCS "github.com/rvflash/goup/testdata/mock/vcs"
 
+	"golang.org/x/mod/module"
+	"golang.org/x/mod/sumdb"
+	"golang.org/x/mod/sumdb/note"
+
 	"go.uber.org/mock/gomock"
 )
 
@@ -34,6 +45,7 @@ func TestGoUp_CheckDependency(t *testing.T) {
 		sy1 = newSystem(ctrl, semver.Tags{semver.New(v0)}, nil)
 		are = is.New(t)
 		dt  = map[string]struct {
+			proxy  vcs.System
 			system vcs.System
 			ctx    context.Context
 			module mod.Module
@@ -64,19 +76,611 @@ func TestGoUp_CheckDependency(t *testing.T) {
 				level:  DebugLevel,
 				format: "up to date",
 			},
+			"proxy failure": {
+				proxy:  newSystem(ctrl, nil, errup.ErrNotFound),
+				system: sy1,
+				ctx:    ctx,
+				module: newModule(ctrl, false),
+				level:  ErrorLevel,
+				format: "check failed",
+			},
+			"ignored module": {
+				system: sy1,
+				ctx:    ctx,
+				module: newModule(ctrl, false),
+				cnf:    Config{Modules: []ModuleConfig{{Path: repoName, Ignore: true}}},
+				level:  DebugLevel,
+				format: "update skipped",
+			},
+			"invalid versions": {
+				system: sy1,
+				ctx:    ctx,
+				module: newModule(ctrl, false),
+				cnf:    Config{Modules: []ModuleConfig{{Path: "*", Versions: "<vx"}}},
+				level:  ErrorLevel,
+				format: "check failed",
+			},
+			"release expected": {
+				system: sy1,
+				ctx:    ctx,
+				module: newModule(ctrl, false),
+				cnf:    Config{Modules: []ModuleConfig{{Path: repoName, OnlyReleases: true, Versions: ">=" + v0}}},
+				level:  DebugLevel,
+				format: "up to date",
+			},
+			"retracted": {
+				system: newModSystem(ctrl, semver.Tags{semver.New(v0), semver.New(v1)}, "retract v0.0.1 // Broken."),
+				ctx:    ctx,
+				module: newModule(ctrl, false),
+				level:  DebugLevel,
+				format: "up to date",
+			},
+			"current retracted": {
+				system: newModSystem(ctrl, semver.Tags{semver.New(v0), semver.New(v1)}, "retract v0.0.0 // Broken."),
+				ctx:    ctx,
+				module: newModule(ctrl, false),
+				level:  WarnLevel,
+				format: "is retracted: Broken., must be updated to",
+			},
+			"proxy direct": {
+				proxy:  newSystem(ctrl, nil, errup.ErrDirect),
+				system: sy1,
+				ctx:    ctx,
+				module: newModule(ctrl, false),
+				level:  DebugLevel,
+				format: "up to date",
+			},
+			"pseudo-version": {
+				system: sy1,
+				ctx:    ctx,
+				module: newPseudoModule(ctrl, pseudo),
+				level:  InfoLevel,
+				format: "day(s) old",
+			},
+			"pseudo-version released": {
+				system: newSystem(ctrl, semver.Tags{semver.New(v0), semver.New(v1), semver.New("v0.1.0")}, nil),
+				ctx:    ctx,
+				module: newPseudoModule(ctrl, pseudo),
+				level:  WarnLevel,
+				format: "must be updated to the release",
+			},
+			"head moved": {
+				system: newHeadSystem(ctrl, &vcs.Revision{Hash: "bccd165b1659b4e2", Time: pseudoTime.Add(time.Hour)}, nil),
+				ctx:    ctx,
+				module: newPseudoModule(ctrl, pseudo),
+				cnf:    Config{CheckHead: true},
+				level:  WarnLevel,
+				format: "must be updated to the last commit",
+			},
+			"head unchanged": {
+				system: newHeadSystem(ctrl, &vcs.Revision{Hash: "accd165b1659b4e2", Time: pseudoTime}, nil),
+				ctx:    ctx,
+				module: newPseudoModule(ctrl, pseudo),
+				cnf:    Config{CheckHead: true},
+				level:  InfoLevel,
+				format: "day(s) old",
+			},
+			"head failure": {
+				system: newHeadSystem(ctrl, nil, errup.ErrFetch),
+				ctx:    ctx,
+				module: newPseudoModule(ctrl, pseudo),
+				cnf:    Config{CheckHead: true},
+				level:  ErrorLevel,
+				format: "check failed",
+			},
+			"head unsupported": {
+				system: sy1,
+				ctx:    ctx,
+				module: newPseudoModule(ctrl, pseudo),
+				cnf:    Config{CheckHead: true},
+				level:  ErrorLevel,
+				format: "check failed",
+			},
 		}
 	)
 	for name, ts := range dt {
 		tt := ts
 		t.Run(name, func(_ *testing.T) {
-			u := newGoUp(tt.cnf, setGoGet(tt.system), setGit(tt.system))
-			e := u.checkDependency(tt.ctx, tt.module)
+			sets := []setter{setGoGet(tt.system), setGit(tt.system)}
+			if tt.proxy != nil {
+				sets = append(sets, setGoProxy(tt.proxy))
+			}
+			u := newGoUp(tt.cnf, sets...)
+			e, _, _ := u.checkDependency(tt.ctx, tt.module)
 			are.Equal(tt.level, e.Level())                    // mismatch level
 			are.True(strings.Contains(e.Format(), tt.format)) // mismatch format
 		})
 	}
 }
 
+func TestGoUp_CheckDependencyWithTimeout(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	var (
+		are  = is.New(t)
+		slow = mockVCS.NewMockSystem(ctrl)
+		busy = mockVCS.NewMockSystem(ctrl)
+	)
+	slow.EXPECT().CanFetch(gomock.Any()).Return(true).AnyTimes()
+	slow.EXPECT().FetchPath(gomock.Any(), repoName).DoAndReturn(func(ctx context.Context, _ string) (semver.Tags, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+	busy.EXPECT().CanFetch(gomock.Any()).Return(true).AnyTimes()
+	busy.EXPECT().FetchPath(gomock.Any(), repoName).DoAndReturn(func(ctx context.Context, _ string) (semver.Tags, error) {
+		var calls int
+		err := vcs.Backoff{Attempts: 2}.Retry(ctx, func() error {
+			if calls++; calls == 1 {
+				return vcs.Temporary(errup.ErrFetch)
+			}
+			return nil
+		})
+		return semver.Tags{semver.New(v0)}, err
+	})
+	// The dependency timeout stops the slow remote.
+	u := newGoUp(Config{DepTimeout: 10 * time.Millisecond}, setGoProxy(slow), setGoGet(slow), setGit(slow))
+	log, _, _ := u.checkDependencyWithTimeout(context.Background(), newModule(ctrl, false))
+	are.True(errors.Is(log.Err(), context.DeadlineExceeded)) // mismatch error
+	// The retried attempts are notified.
+	u = newGoUp(Config{}, setGoProxy(busy), setGoGet(busy), setGit(busy))
+	done := make(chan Message)
+	go func() {
+		defer close(done)
+		for msg := range u.log {
+			done <- msg
+		}
+	}()
+	log, _, _ = u.checkDependencyWithTimeout(context.Background(), newModule(ctrl, false))
+	close(u.log)
+	are.Equal(log.Status(), UpToDate) // mismatch status
+	msg := <-done
+	are.Equal(msg.Level(), DebugLevel) // mismatch level
+	are.Equal(fmt.Sprintf(msg.Format(), msg.Args()...), repoName+": "+v0+
+		" checked after 2 attempt(s), 1 retried on temporary failures") // mismatch message
+}
+
+func TestGoUp_CheckDependencies(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	var (
+		are = is.New(t)
+		dt  = map[string]struct {
+			cnf     Config
+			failed  int
+			aborted int
+		}{
+			"default": {failed: 3},
+			"strict":  {cnf: Config{Strict: true}, failed: 1, aborted: 2},
+		}
+	)
+	for name, ts := range dt {
+		tt := ts
+		t.Run(name, func(_ *testing.T) {
+			f := mockMod.NewMockMod(ctrl)
+			f.EXPECT().Module().Return(repoName).AnyTimes()
+			f.EXPECT().Dependencies().Return([]mod.Module{
+				newModule(ctrl, false), newModule(ctrl, false), newModule(ctrl, false),
+			}).Times(oneTime)
+			sy := newSystem(ctrl, nil, errup.ErrFetch)
+			u := newGoUp(tt.cnf, setGoProxy(sy), setGoGet(sy), setGit(sy))
+			res := make(map[Status]int)
+			go func() {
+				defer close(u.log)
+				are.Equal(u.checkDependencies(context.Background(), f), uint64(3)) // mismatch bad
+			}()
+			for msg := range u.log {
+				if msg.Path() != "" {
+					res[msg.Status()]++
+				}
+			}
+			are.Equal(res[Failed], tt.failed)   // mismatch failed
+			are.Equal(res[Aborted], tt.aborted) // mismatch aborted
+		})
+	}
+}
+
+func TestGoUp_CheckFile(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	var (
+		are = is.New(t)
+		dep = newModule(ctrl, false)
+		f   = mockMod.NewMockMod(ctrl)
+		sy  = newSystem(ctrl, semver.Tags{semver.New(v0), semver.New(v1)}, nil)
+	)
+	dep.EXPECT().Replacement().Return(false).Times(oneTime)
+	f.EXPECT().Module().Return(repoName).AnyTimes()
+	f.EXPECT().Dependencies().Return([]mod.Module{dep}).Times(oneTime)
+	// In diff mode, the update is only applied in memory: the file is neither formatted nor written.
+	f.EXPECT().UpdateRequire(repoName, v1).Return(nil).Times(oneTime)
+	f.EXPECT().Go().Return("1.21").Times(oneTime)
+	f.EXPECT().Toolchain().Return("go1.21.1").Times(oneTime)
+	f.EXPECT().UpdateToolchain("go1.21.5").Return(nil).Times(oneTime)
+	u := newGoUp(Config{Diff: true, UpdateDirectives: true, Timeout: time.Second},
+		setGoProxy(sy), setGoGet(sy), setGit(sy), newReleases(nil))
+	go u.checkFile(context.Background(), f)
+	var (
+		res     []Status
+		skipped int
+	)
+	for msg := range u.log {
+		if strings.Contains(msg.Format(), "go.mod not read") {
+			skipped++
+			continue
+		}
+		res = append(res, msg.Status())
+	}
+	are.Equal(res, []Status{Updated, UpToDate, Updated}) // mismatch statuses
+	are.Equal(skipped, 1)                                // the go.mod file of the dependency is not read
+}
+
+func TestGoUp_CheckFile_Rewrite(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	var (
+		are     = is.New(t)
+		dir     = t.TempDir()
+		oldPath = repoName + "/v2"
+		newPath = repoName + "/v3"
+		src     = filepath.Join(dir, "main.go")
+		dep     = newPathModule(ctrl, oldPath, "v2.4.0")
+		f       = mockMod.NewMockMod(ctrl)
+		tags    = semver.Tags{semver.New("v2.4.0"), semver.New("v3.1.0")}
+		// The repository lists the tags of every major.
+		git = newSystem(ctrl, tags, nil)
+		no  = newNoSystem(ctrl)
+	)
+	err := os.WriteFile(src, []byte("package main\n\nimport _ \""+oldPath+"\"\n"), 0o600)
+	are.NoErr(err) // unexpected error
+	dep.EXPECT().Indirect().Return(false).AnyTimes()
+	dep.EXPECT().ExcludeVersions().Return(nil).AnyTimes()
+	dep.EXPECT().Replacement().Return(false).AnyTimes()
+	f.EXPECT().Module().Return(repoName).AnyTimes()
+	f.EXPECT().Dependencies().Return([]mod.Module{dep}).Times(oneTime)
+	f.EXPECT().MoveRequire(oldPath, newPath, "v3.1.0").Return(nil).Times(oneTime)
+	f.EXPECT().Go().Return("").Times(oneTime)
+	f.EXPECT().Toolchain().Return("").Times(oneTime)
+	f.EXPECT().Format().Return([]byte("module "+repoName+"\n"), nil).Times(oneTime)
+	f.EXPECT().Changes().Return(nil, nil).Times(oneTime)
+	f.EXPECT().Name().Return(filepath.Join(dir, "go.mod")).AnyTimes()
+	u := newGoUp(Config{ForceUpdate: true, Rewrite: repoName + "/*", Timeout: time.Second},
+		setGoProxy(no), setGoGet(no), setGit(git), newReleases(nil))
+	go u.checkFile(context.Background(), f)
+	var res []Message
+	for msg := range u.log {
+		if !strings.Contains(msg.Format(), "go.mod not read") {
+			res = append(res, msg)
+		}
+	}
+	are.Equal(len(res), 3)                   // mismatch messages
+	are.Equal(res[0].Status(), UpToDate)     // the module is up to date in its major
+	are.Equal(res[1].Status(), Updated)      // mismatch status
+	are.Equal(res[1].NewPath(), newPath)     // mismatch path
+	are.Equal(res[1].NewVersion(), "v3.1.0") // mismatch version
+	are.Equal(res[2].Args()[3], src)         // mismatch files rewritten
+	b, err := os.ReadFile(src)
+	are.NoErr(err)                                                     // unexpected error
+	are.Equal(string(b), "package main\n\nimport _ \""+newPath+"\"\n") // mismatch imports
+}
+
+func TestGoUp_UpdateSum(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	var (
+		are   = is.New(t)
+		dir   = t.TempDir()
+		name  = filepath.Join(dir, sum.Filename)
+		f     = mockMod.NewMockMod(ctrl)
+		proxy = zipSystem{
+			MockSystem:     newSystem(ctrl, nil, nil),
+			MockModFetcher: mockVCS.NewMockModFetcher(ctrl),
+			MockZipFetcher: mockVCS.NewMockZipFetcher(ctrl),
+		}
+		modContent = []byte("module " + repoName + "\n\ngo 1.14\n\n" +
+			"require (\n\texample.com/dep v1.2.0\n\texample.com/other v1.0.0\n)\n")
+		depContent = []byte("module example.com/dep\n")
+		zipContent = newZip(t, repoName+"@"+v1+"/go.mod", string(modContent))
+	)
+	modHash, err := sum.HashMod(modContent)
+	are.NoErr(err) // unexpected error
+	zipHash, err := sum.HashZip(zipContent)
+	are.NoErr(err) // unexpected error
+	depHash, err := sum.HashMod(depContent)
+	are.NoErr(err) // unexpected error
+	err = os.WriteFile(name, []byte(
+		repoName+" "+v0+" h1:zip=\n"+
+			repoName+" "+v0+"/go.mod h1:mod=\n"+
+			"example.com/other v1.0.0/go.mod h1:other=\n",
+	), 0o600)
+	are.NoErr(err) // unexpected error
+	proxy.MockModFetcher.EXPECT().FetchMod(gomock.Any(), repoName, v1).Return(modContent, nil).Times(oneTime)
+	proxy.MockZipFetcher.EXPECT().FetchZip(gomock.Any(), repoName, v1).Return(zipContent, nil).Times(oneTime)
+	// Only the go.mod file of the requirement not hashed yet is fetched.
+	proxy.MockModFetcher.EXPECT().FetchMod(gomock.Any(), "example.com/dep", "v1.2.0").Return(depContent, nil).Times(oneTime)
+	proxy.MockModFetcher.EXPECT().FetchMod(gomock.Any(), "example.com/other", "v1.1.0").
+		Return(nil, errup.ErrNotFound).Times(oneTime)
+	f.EXPECT().Module().Return(repoName).AnyTimes()
+	f.EXPECT().Name().Return(filepath.Join(dir, "go.mod")).AnyTimes()
+	f.EXPECT().Changes().Return(
+		[]module.Version{{Path: repoName, Version: v1}, {Path: "example.com/other", Version: "v1.1.0"}},
+		[]module.Version{{Path: repoName, Version: v0}},
+	).Times(oneTime)
+	u := newGoUp(Config{ForceUpdate: true}, setGoProxy(proxy))
+	go func() {
+		defer close(u.log)
+		u.updateSum(context.Background(), f)
+	}()
+	var res []Message
+	for msg := range u.log {
+		res = append(res, msg)
+	}
+	are.Equal(len(res), 2)                                                    // mismatch messages
+	are.Equal(res[0].Level(), WarnLevel)                                      // the hash of other is missing
+	are.Equal(res[1].Args(), []interface{}{repoName, sum.Filename, "3", "1"}) // mismatch summary
+	b, err := os.ReadFile(name)
+	are.NoErr(err) // unexpected error
+	are.Equal(string(b), "example.com/dep v1.2.0/go.mod "+depHash+"\n"+
+		repoName+" "+v0+"/go.mod h1:mod=\n"+
+		repoName+" "+v1+" "+zipHash+"\n"+
+		repoName+" "+v1+"/go.mod "+modHash+"\n"+
+		"example.com/other v1.0.0/go.mod h1:other=\n") // mismatch go.sum
+}
+
+func TestGoUp_CheckFile_SumDB(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	var (
+		are = is.New(t)
+		f   = mockMod.NewMockMod(ctrl)
+		no  = newNoSystem(ctrl)
+	)
+	f.EXPECT().Module().Return(repoName).AnyTimes()
+	u := newGoUp(Config{VerifySum: true, Timeout: time.Second},
+		setGoProxy(no), setGoGet(no), setGit(no), newReleases(nil), setSumDB(nil, errup.ErrSystem))
+	go u.checkFile(context.Background(), f)
+	var res []Message
+	for msg := range u.log {
+		res = append(res, msg)
+	}
+	are.Equal(len(res), 1)                             // mismatch messages
+	are.True(errors.Is(res[0].Err(), errup.ErrSystem)) // without checksum database, no version is advised
+}
+
+func TestGoUp_Verify(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	var (
+		are = is.New(t)
+		db  = newSumDB(t, "example.com/private")
+		dep = newPathModule(ctrl, repoName, v0)
+		ctx = context.Background()
+		dt  = map[string]struct {
+			db     *sum.DB
+			in     *Entry
+			status Status
+		}{
+			"default":       {db: db, status: Failed},
+			"no database":   {in: newOutOfDate(dep, "v0.9.0"), status: Outdated},
+			"up to date":    {db: db, in: newCheck(dep), status: UpToDate},
+			"known":         {db: db, in: newOutOfDate(dep, v1), status: Outdated},
+			"unknown":       {db: db, in: newOutOfDate(dep, "v0.9.0"), status: Failed},
+			"major known":   {db: db, in: newMajorAvailable(dep, repoName+"/v2", "v2.0.0"), status: MajorAvailable},
+			"major unknown": {db: db, in: newMajorAvailable(dep, repoName+"/v3", "v3.0.0"), status: Failed},
+			"not to verify": {db: db, in: newOutOfDate(newPathModule(ctrl, "example.com/private/pkg", v0), v1), status: Outdated},
+		}
+	)
+	for name, ts := range dt {
+		tt := ts
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			u := &goUp{sumDB: tt.db}
+			res := u.verify(ctx, dep, tt.in)
+			are.Equal(res.Status(), tt.status) // mismatch status
+			if tt.status == Failed {
+				are.Equal(res.NewVersion(), "") // the version must not be advised
+			}
+		})
+	}
+}
+
+func TestGoUp_CheckDirectives(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	var (
+		are = is.New(t)
+		dt  = map[string]struct {
+			cnf       Config
+			goVersion string
+			toolchain string
+			releases  error
+			update    string
+			updateErr error
+			res       []string
+			bad       uint64
+		}{
+			"default":    {},
+			"no release": {goVersion: "1.19", releases: errup.ErrFetch, res: []string{"directives check skipped"}},
+			"up to date": {goVersion: "1.20", toolchain: "go1.21.5", res: []string{"go: 1.20 is up to date", "toolchain: go1.21.5 is up to date"}},
+			"default toolchain": {
+				goVersion: "1.21",
+				toolchain: release.Default,
+				res:       []string{"1.21 is up to date"},
+			},
+			"unsupported": {
+				goVersion: "1.19.13",
+				res:       []string{"go: 1.19.13 is no longer supported, must be updated to 1.20.12"},
+			},
+			"unsupported minor": {
+				cnf:       Config{MajorMinor: true},
+				goVersion: "1.19",
+				res:       []string{"go: 1.19 is no longer supported, must be updated to 1.21.5"},
+			},
+			"outdated toolchain": {
+				toolchain: "go1.20.1",
+				res:       []string{"toolchain: go1.20.1 must be updated to go1.20.12"},
+			},
+			"unsupported forced": {
+				cnf:       Config{ForceUpdate: true},
+				goVersion: "1.19.13",
+				res:       []string{"go: 1.19.13 is no longer supported, must be updated to 1.20.12"},
+			},
+			"update patch": {
+				cnf:       Config{ForceUpdate: true, UpdateDirectives: true},
+				goVersion: "1.19.13",
+				update:    "1.20.12",
+				res:       []string{"go: 1.19.13 will be updated to 1.20.12"},
+			},
+			"update not asked": {
+				cnf:       Config{ForceUpdate: true, Major: true},
+				goVersion: "1.19.2",
+				res:       []string{"go: 1.19.2 is no longer supported, must be updated to 1.21.5"},
+			},
+			"update": {
+				cnf:       Config{ForceUpdate: true, Major: true, UpdateDirectives: true},
+				goVersion: "1.19.2",
+				update:    "1.21.5",
+				res:       []string{"go: 1.19.2 will be updated to 1.21.5"},
+			},
+			"update failure": {
+				cnf:       Config{ForceUpdate: true, Major: true, UpdateDirectives: true},
+				goVersion: "1.19.2",
+				update:    "1.21.5",
+				updateErr: errup.ErrMod,
+				res:       []string{"check failed"},
+				bad:       1,
+			},
+		}
+	)
+	for name, ts := range dt {
+		tt := ts
+		t.Run(name, func(_ *testing.T) {
+			f := mockMod.NewMockMod(ctrl)
+			f.EXPECT().Module().Return(repoName).AnyTimes()
+			f.EXPECT().Go().Return(tt.goVersion).Times(oneTime)
+			f.EXPECT().Toolchain().Return(tt.toolchain).Times(oneTime)
+			if tt.update != "" {
+				f.EXPECT().UpdateGo(tt.update).Return(tt.updateErr).Times(oneTime)
+			}
+			u := newGoUp(tt.cnf, newReleases(tt.releases))
+			go func() {
+				defer close(u.log)
+				are.Equal(u.checkDirectives(context.Background(), f), tt.bad) // mismatch bad
+			}()
+			var res []string
+			for msg := range u.log {
+				res = append(res, fmt.Sprintf(msg.Format(), msg.Args()...))
+			}
+			are.Equal(len(res), len(tt.res)) // mismatch number of messages
+			for k, s := range tt.res {
+				are.True(strings.Contains(res[k], s)) // mismatch message
+			}
+		})
+	}
+}
+
+func TestGoUp_CheckDependencies_Deprecated(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	var (
+		are = is.New(t)
+		dt  = map[string]struct {
+			level string
+			out   Level
+			bad   uint64
+		}{
+			"default": {out: WarnLevel},
+			"error":   {level: DeprecatedError, out: ErrorLevel, bad: 1},
+			"info":    {level: DeprecatedInfo, out: InfoLevel},
+		}
+	)
+	for name, ts := range dt {
+		tt := ts
+		t.Run(name, func(_ *testing.T) {
+			f := mockMod.NewMockMod(ctrl)
+			f.EXPECT().Dependencies().Return([]mod.Module{newModule(ctrl, false)}).Times(oneTime)
+			sy := newModSystem(ctrl, semver.Tags{semver.New(v0), semver.New(v1)}, "")
+			sy.(modSystem).MockModFetcher.EXPECT().FetchMod(gomock.Any(), repoName, v1).
+				Return([]byte("// Deprecated: use example.com/go.\nmodule "+repoName+"\n"), nil).Times(oneTime)
+			u := newGoUp(Config{Deprecated: tt.level}, setGoProxy(sy), setGoGet(sy), setGit(sy))
+			go func() {
+				defer close(u.log)
+				// The dependency is also outdated.
+				are.Equal(u.checkDependencies(context.Background(), f), tt.bad+1) // mismatch bad
+			}()
+			var res []Message
+			for msg := range u.log {
+				if msg.Status() == Deprecated {
+					res = append(res, msg)
+				}
+			}
+			are.Equal(len(res), 1)                                            // mismatch deprecation
+			are.Equal(res[0].Level(), tt.out)                                 // mismatch level
+			are.True(strings.Contains(res[0].Format(), "use example.com/go")) // mismatch message
+		})
+	}
+}
+
+func TestLocate(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	var (
+		are  = is.New(t)
+		file = mockMod.NewMockMod(ctrl)
+		in   = make(chan Message, 1)
+	)
+	file.EXPECT().Name().Return("go.mod").AnyTimes()
+	file.EXPECT().Module().Return(repoName).AnyTimes()
+	in <- newOutOfDate(newModule(ctrl, false), v1)
+	close(in)
+	are.Equal(locate(in, nil), in) // expected same channel without file
+	for msg := range locate(in, file) {
+		are.Equal(msg.File(), "go.mod")          // mismatch file
+		are.Equal(msg.Module(), repoName)        // mismatch module
+		are.Equal(msg.UpdateKind(), PatchUpdate) // mismatch kind
+	}
+}
+
+func TestConfig_Module(t *testing.T) {
+	t.Parallel()
+	var (
+		are = is.New(t)
+		dt  = map[string]struct {
+			cnf Config
+			in  string
+			out ModuleConfig
+		}{
+			"default": {out: ModuleConfig{Mode: PatchMode}},
+			"global":  {cnf: Config{MajorMinor: true}, in: repoName, out: ModuleConfig{Path: repoName, Mode: MinorMode}},
+			"override": {
+				cnf: Config{Major: true, Modules: []ModuleConfig{
+					{Path: "example.com/*", Ignore: true},
+					{Path: "github.com/*/*", Mode: PatchMode, Versions: "<v2"},
+					{Path: "*", Mode: MinorMode},
+				}},
+				in:  "github.com/rvflash/goup",
+				out: ModuleConfig{Path: "github.com/rvflash/goup", Mode: PatchMode, Versions: "<v2"},
+			},
+		}
+	)
+	for name, ts := range dt {
+		tt := ts
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			are.Equal(tt.cnf.module(tt.in), tt.out) // mismatch result
+		})
+	}
+}
+
 func TestUpdateFile(t *testing.T) {
 	t.Parallel()
 	ctrl := gomock.NewController(t)
@@ -144,6 +748,12 @@ func TestLatest(t *testing.T) {
 				out: semver.New("v0.2.3"),
 				ok:  true,
 			},
+			"pseudo-version": {
+				in:  res,
+				dep: newVer(ctrl, "v0.1.3-0.20200121190230-accd165b1659"),
+				out: semver.New("v0.2.3"),
+				ok:  true,
+			},
 		}
 	)
 	for name, ts := range dt {
@@ -156,6 +766,141 @@ func TestLatest(t *testing.T) {
 	}
 }
 
+func TestInPath(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	var (
+		res = semver.Tags{
+			semver.New("v0.1.0"),
+			semver.New("v1.2.0"),
+			semver.New("v2.0.0"),
+			semver.New("v2.1.0"),
+			semver.New("v3.1.0"),
+			semver.New("v4.0.0+incompatible"),
+		}
+		are = is.New(t)
+		dt  = map[string]struct {
+			dep mod.Module
+			out []string
+		}{
+			"v1":           {dep: newPathModule(ctrl, repoName, "v1.2.0"), out: []string{"v0.1.0", "v1.2.0", "v4.0.0+incompatible"}},
+			"v2":           {dep: newPathModule(ctrl, repoName+"/v2", "v2.0.0"), out: []string{"v2.0.0", "v2.1.0"}},
+			"v3":           {dep: newPathModule(ctrl, repoName+"/v3", "v3.1.0"), out: []string{"v3.1.0"}},
+			"incompatible": {dep: newPathModule(ctrl, repoName, "v2.0.0+incompatible"), out: []string{"v0.1.0", "v1.2.0", "v2.0.0+incompatible", "v2.1.0+incompatible", "v4.0.0+incompatible"}},
+			"gopkg.in v1":  {dep: newPathModule(ctrl, "gopkg.in/yaml.v1", "v1.2.0"), out: []string{"v1.2.0"}},
+			"gopkg.in v2":  {dep: newPathModule(ctrl, "gopkg.in/yaml.v2", "v2.0.0"), out: []string{"v2.0.0", "v2.1.0"}},
+		}
+	)
+	for name, ts := range dt {
+		tt := ts
+		t.Run(name, func(_ *testing.T) {
+			out := inPath(res, tt.dep)
+			are.Equal(len(out), len(tt.out)) // mismatch number of versions
+			for k, v := range out {
+				are.Equal(v.String(), tt.out[k]) // mismatch version
+			}
+		})
+	}
+}
+
+func TestGoUp_CheckDependencies_Major(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	var (
+		are     = is.New(t)
+		oldPath = repoName + "/v2"
+		dep     = newPathModule(ctrl, oldPath, "v2.4.0")
+		f       = mockMod.NewMockMod(ctrl)
+		tags    = semver.Tags{semver.New("v2.4.0"), semver.New("v2.5.0"), semver.New("v3.1.0")}
+		git     = newSystem(ctrl, tags, nil)
+		no      = newNoSystem(ctrl)
+	)
+	dep.EXPECT().Indirect().Return(false).AnyTimes()
+	dep.EXPECT().ExcludeVersions().Return(nil).AnyTimes()
+	dep.EXPECT().Replacement().Return(false).AnyTimes()
+	f.EXPECT().Dependencies().Return([]mod.Module{dep}).Times(oneTime)
+	// The major available is only noticed, the update in the current major is still applied.
+	f.EXPECT().UpdateRequire(oldPath, "v2.5.0").Return(nil).Times(oneTime)
+	u := newGoUp(Config{Major: true, ForceUpdate: true}, setGoProxy(no), setGoGet(no), setGit(git))
+	go func() {
+		defer close(u.log)
+		are.Equal(u.checkDependencies(context.Background(), f), uint64(0)) // mismatch bad
+	}()
+	res := make(map[Status]int)
+	for msg := range u.log {
+		res[msg.Status()]++
+	}
+	are.Equal(res[MajorAvailable], 1) // mismatch major available
+	are.Equal(res[Updated], 1)        // mismatch updated
+}
+
+func TestGoUp_CheckMajor(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	var (
+		are  = is.New(t)
+		tags = semver.Tags{semver.New("v1.2.0"), semver.New("v2.4.0"), semver.New("v3.1.0"), semver.New("v4.0.0-rc.1")}
+		git  = newSystem(ctrl, tags, nil)
+		// The Go module proxy only lists the versions of the requested path.
+		proxy = mockVCS.NewMockSystem(ctrl)
+	)
+	proxy.EXPECT().FetchPath(gomock.Any(), repoName+"/v3").Return(semver.Tags{semver.New("v3.0.0"), semver.New("v3.1.0")}, nil).AnyTimes()
+	proxy.EXPECT().FetchPath(gomock.Any(), repoName+"/v4").Return(nil, errup.ErrNotFound).AnyTimes()
+	proxy.EXPECT().FetchPath(gomock.Any(), "gopkg.in/yaml.v3").Return(semver.Tags{semver.New("v3.0.1")}, nil).AnyTimes()
+	proxy.EXPECT().FetchPath(gomock.Any(), "gopkg.in/yaml.v4").Return(nil, errup.ErrNotFound).AnyTimes()
+	// The new major has moved to another module path.
+	migrated := modSystem{MockSystem: newSystem(ctrl, tags, nil), MockModFetcher: mockVCS.NewMockModFetcher(ctrl)}
+	migrated.MockModFetcher.EXPECT().FetchMod(gomock.Any(), "gopkg.in/yaml.v3", "v3.1.0").
+		Return([]byte("module go.yaml.in/yaml/v3\n"), nil).AnyTimes()
+	migrated.MockModFetcher.EXPECT().FetchMod(gomock.Any(), repoName+"/v3", "v3.1.0").
+		Return([]byte("module "+repoName+"\n"), nil).AnyTimes()
+	var (
+		u  = newGoUp(Config{Major: true}, setGoProxy(proxy), setGoGet(git), setGit(git))
+		dt = map[string]struct {
+			system  vcs.System
+			dep     mod.Module
+			allowed string
+			path    string
+			version string
+		}{
+			"git":          {system: git, dep: newPathModule(ctrl, repoName+"/v2", "v2.4.0"), path: repoName + "/v3", version: "v3.1.0"},
+			"git v1":       {system: git, dep: newPathModule(ctrl, repoName, "v1.2.0"), path: repoName + "/v3", version: "v3.1.0"},
+			"git latest":   {system: git, dep: newPathModule(ctrl, repoName+"/v3", "v3.1.0")},
+			"not allowed":  {system: git, dep: newPathModule(ctrl, repoName+"/v2", "v2.4.0"), allowed: "<v3"},
+			"proxy":        {system: proxy, dep: newPathModule(ctrl, repoName+"/v2", "v2.4.0"), path: repoName + "/v3", version: "v3.1.0"},
+			"incompatible": {system: git, dep: newPathModule(ctrl, repoName, "v2.0.0+incompatible"), path: repoName + "/v3", version: "v3.1.0"},
+			"gopkg.in":     {system: git, dep: newPathModule(ctrl, "gopkg.in/yaml.v2", "v2.4.0"), path: "gopkg.in/yaml.v3", version: "v3.1.0"},
+			"gopkg.in v0":  {system: git, dep: newPathModule(ctrl, "gopkg.in/yaml.v0", "v0.1.0"), path: "gopkg.in/yaml.v3", version: "v3.1.0"},
+			"gopkg.in proxy": {
+				system: proxy, dep: newPathModule(ctrl, "gopkg.in/yaml.v2", "v2.4.0"), path: "gopkg.in/yaml.v3", version: "v3.0.1",
+			},
+			"migrated": {system: migrated, dep: newPathModule(ctrl, "gopkg.in/yaml.v2", "v2.4.0"), path: "go.yaml.in/yaml/v3", version: "v3.1.0"},
+			// The path declared without major suffix can not be used to require a v3 version.
+			"invalid migration": {system: migrated, dep: newPathModule(ctrl, repoName+"/v2", "v2.4.0"), path: repoName + "/v3", version: "v3.1.0"},
+		}
+	)
+	for name, ts := range dt {
+		tt := ts
+		t.Run(name, func(_ *testing.T) {
+			allowed, err := semver.ParseRange(tt.allowed)
+			are.NoErr(err) // unexpected error
+			e := u.checkMajor(context.Background(), tt.system, tt.dep, tags, allowed)
+			if tt.path == "" {
+				are.Equal(e, nil) // unexpected major
+				return
+			}
+			are.Equal(e.Status(), MajorAvailable) // mismatch status
+			are.Equal(e.NewPath(), tt.path)       // mismatch path
+			are.Equal(e.NewVersion(), tt.version) // mismatch version
+			_, ok := e.OutDated()
+			are.True(!ok) // the module path can not be updated in place
+		})
+	}
+}
+
 func TestOnlyTag(t *testing.T) {
 	t.Parallel()
 	ctrl := gomock.NewController(t)
@@ -201,6 +946,37 @@ func newModule(ctrl *gomock.Controller, indirect bool) *mockMod.MockModule {
 	return m
 }
 
+func newPseudoModule(ctrl *gomock.Controller, v string) *mockMod.MockModule {
+	m := mockMod.NewMockModule(ctrl)
+	m.EXPECT().Path().Return(repoName).AnyTimes()
+	m.EXPECT().Version().Return(semver.New(v)).AnyTimes()
+	m.EXPECT().Indirect().Return(false).AnyTimes()
+	m.EXPECT().ExcludeVersions().Return(nil).AnyTimes()
+	return m
+}
+
+func newPathModule(ctrl *gomock.Controller, path, v string) *mockMod.MockModule {
+	m := mockMod.NewMockModule(ctrl)
+	m.EXPECT().Path().Return(path).AnyTimes()
+	m.EXPECT().Version().Return(semver.New(v)).AnyTimes()
+	return m
+}
+
+func newReleases(err error) setter {
+	return setReleases(func(_ context.Context) (release.Releases, error) {
+		if err != nil {
+			return nil, err
+		}
+		return release.Releases{
+			{Version: "go1.22rc1"},
+			{Version: "go1.21.5", Stable: true},
+			{Version: "go1.20.12", Stable: true},
+			{Version: "go1.20.1", Stable: true},
+			{Version: "go1.19.13", Stable: true},
+		}, nil
+	})
+}
+
 func newNoSystem(ctrl *gomock.Controller) *mockVCS.MockSystem {
 	m := mockVCS.NewMockSystem(ctrl)
 	m.EXPECT().CanFetch(gomock.Any()).Return(false).AnyTimes()
@@ -214,6 +990,92 @@ func newSystem(ctrl *gomock.Controller, tags semver.Tags, err error) *mockVCS.Mo
 	return m
 }
 
+type modSystem struct {
+	*mockVCS.MockSystem
+	*mockVCS.MockModFetcher
+}
+
+// newModSystem returns a system publishing the go.mod file of the module with these directives.
+// Without directive, the go.mod file is not expected.
+func newModSystem(ctrl *gomock.Controller, tags semver.Tags, directives string) vcs.System {
+	m := modSystem{
+		MockSystem:     newSystem(ctrl, tags, nil),
+		MockModFetcher: mockVCS.NewMockModFetcher(ctrl),
+	}
+	if directives != "" {
+		content := []byte("module " + repoName + "\n\n" + directives + "\n")
+		m.MockModFetcher.EXPECT().FetchMod(gomock.Any(), repoName, v1).Return(content, nil).AnyTimes()
+	}
+	return m
+}
+
+// newSumDB returns a checksum database served by a local test server,
+// knowing the v0.0.1 of the module and the v2.0.0 of its v2.
+func newSumDB(t *testing.T, noSumDB string) *sum.DB {
+	t.Helper()
+	skey, vkey, err := note.GenerateKey(rand.Reader, "sum.example.test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := httptest.NewServer(sumdb.NewServer(sumdb.NewTestServer(skey, func(path, version string) ([]byte, error) {
+		switch {
+		case path == repoName && version == v1, path == repoName+"/v2" && version == "v2.0.0":
+			return []byte(path + " " + version + " h1:zip=\n" + path + " " + version + sum.ModSuffix + " h1:mod=\n"), nil
+		default:
+			return nil, os.ErrNotExist
+		}
+	})))
+	t.Cleanup(srv.Close)
+	db, err := sum.NewDB(vcs.NewHTTPClient(time.Second, ""), nil, vkey+" "+srv.URL, noSumDB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+type zipSystem struct {
+	*mockVCS.MockSystem
+	*mockVCS.MockModFetcher
+	*mockVCS.MockZipFetcher
+}
+
+// newZip returns a zip archive with these file names and contents.
+func newZip(t *testing.T, nameContent ...string) []byte {
+	t.Helper()
+	var (
+		buf bytes.Buffer
+		w   = zip.NewWriter(&buf)
+	)
+	for i := 0; i+1 < len(nameContent); i += 2 {
+		f, err := w.Create(nameContent[i])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err = f.Write([]byte(nameContent[i+1])); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+type headSystem struct {
+	*mockVCS.MockSystem
+	*mockVCS.MockHeadFetcher
+}
+
+// newHeadSystem returns a system without release, reading this last commit of the default branch.
+func newHeadSystem(ctrl *gomock.Controller, head *vcs.Revision, err error) vcs.System {
+	m := headSystem{
+		MockSystem:      newSystem(ctrl, nil, nil),
+		MockHeadFetcher: mockVCS.NewMockHeadFetcher(ctrl),
+	}
+	m.MockHeadFetcher.EXPECT().FetchHead(gomock.Any(), repoName).Return(head, err).AnyTimes()
+	return m
+}
+
 func newTag(ctrl *gomock.Controller, v string) *mockMod.MockModule {
 	d := mockMod.NewMockModule(ctrl)
 	d.EXPECT().Path().Return(repoName).Times(oneTime)
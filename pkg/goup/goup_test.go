@@ -36,4 +36,83 @@ func TestEntry_OutDated(t *testing.T) {
 	v, ok := msg.OutDated()
 	are.True(!ok)    // not outdated
 	are.Equal("", v) // no new version expected
+	// The proposed version does not depend on the arguments of the message.
+	msg = &goup.Entry{Kind: goup.WarnLevel, Message: "outdated", State: goup.Outdated, Proposed: "v1.0.0"}
+	v, ok = msg.OutDated()
+	are.True(ok)           // outdated
+	are.Equal("v1.0.0", v) // mismatch new version
+	msg.State = goup.Drifted
+	_, ok = msg.OutDated()
+	are.True(!ok) // not outdated
+}
+
+func TestEntry_UpdateKind(t *testing.T) {
+	t.Parallel()
+	var (
+		are = is.New(t)
+		dt  = map[string]struct {
+			in  *goup.Entry
+			out goup.UpdateKind
+		}{
+			"default":    {out: goup.NoUpdate},
+			"up to date": {in: &goup.Entry{Current: "v1.0.0"}, out: goup.NoUpdate},
+			"patch":      {in: &goup.Entry{Current: "v1.0.0", Proposed: "v1.0.1"}, out: goup.PatchUpdate},
+			"minor":      {in: &goup.Entry{Current: "v1.0.0", Proposed: "v1.1.0"}, out: goup.MinorUpdate},
+			"major":      {in: &goup.Entry{Current: "v1.0.0", Proposed: "v2.0.0"}, out: goup.MajorUpdate},
+			"prerelease": {in: &goup.Entry{Current: "v1.0.0", Proposed: "v1.1.0-rc.1"}, out: goup.PrereleaseUpdate},
+			"pseudo": {
+				in:  &goup.Entry{Current: "v1.0.0", Proposed: "v1.0.1-0.20200121190230-accd165b1659"},
+				out: goup.PseudoUpdate,
+			},
+			"go directive": {
+				in:  &goup.Entry{Dep: "go", Current: "1.19", Proposed: "1.21.5", IsDirective: true},
+				out: goup.MinorUpdate,
+			},
+			"toolchain": {
+				in:  &goup.Entry{Dep: "toolchain", Current: "go1.21.1", Proposed: "go1.21.5", IsDirective: true},
+				out: goup.PatchUpdate,
+			},
+		}
+	)
+	for name, ts := range dt {
+		tt := ts
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			are.Equal(tt.in.UpdateKind(), tt.out) // mismatch kind
+		})
+	}
+}
+
+func TestStatus_String(t *testing.T) {
+	t.Parallel()
+	var (
+		are = is.New(t)
+		dt  = map[string]struct {
+			in  goup.Status
+			out string
+		}{
+			"default":    {out: "unknown"},
+			"failed":     {in: goup.Failed, out: "failed"},
+			"outdated":   {in: goup.Outdated, out: "outdated"},
+			"skipped":    {in: goup.Skipped, out: "skipped"},
+			"up-to-date": {in: goup.UpToDate, out: "up-to-date"},
+			"updated":    {in: goup.Updated, out: "updated"},
+			"aborted":    {in: goup.Aborted, out: "aborted"},
+			"retracted":  {in: goup.Retracted, out: "retracted"},
+			"deprecated": {in: goup.Deprecated, out: "deprecated"},
+			"drifted":    {in: goup.Drifted, out: "drifted"},
+			"major":      {in: goup.MajorAvailable, out: "major-available"},
+			"unknown":    {in: goup.Status(42)},
+		}
+	)
+	for name, ts := range dt {
+		tt := ts
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			b, err := tt.in.MarshalText()
+			are.NoErr(err)                    // unexpected error
+			are.Equal(string(b), tt.out)      // mismatch text
+			are.Equal(tt.in.String(), tt.out) // mismatch string
+		})
+	}
 }
@@ -0,0 +1,188 @@
+// Copyright (c) 2020 Hervé Gouchet. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package goup
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/rvflash/goup/internal/release"
+	"github.com/rvflash/goup/internal/sum"
+	"github.com/rvflash/goup/internal/vcs"
+	"github.com/rvflash/goup/internal/vcs/cache"
+	"github.com/rvflash/goup/internal/vcs/flight"
+	"github.com/rvflash/goup/internal/vcs/git"
+	"github.com/rvflash/goup/internal/vcs/goget"
+	"github.com/rvflash/goup/internal/vcs/goproxy"
+	"github.com/rvflash/goup/internal/vcs/hg"
+	"github.com/rvflash/goup/internal/vcs/svn"
+)
+
+// newResolver returns a new resolver, to share between the checks of a run.
+func newResolver() *resolver {
+	return &resolver{
+		memory:  cache.NewMemory(),
+		group:   flight.NewGroup(),
+		systems: make(map[string]*systems),
+	}
+}
+
+// resolver shares the remote properties between the checks of the go.mod files of a run.
+// The tags of a dependency are only fetched once, even if several files request them at the same time.
+// The systems are built once by set of remote settings, so their HTTP clients are reused too.
+type resolver struct {
+	memory *cache.Memory
+	group  *flight.Group
+
+	mu      sync.Mutex
+	systems map[string]*systems
+}
+
+// get returns the systems matching the remote settings of this configuration.
+func (r *resolver) get(conf Config) *systems {
+	if r == nil {
+		return newSystems(conf, nil, nil)
+	}
+	key := fingerprint(conf)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s, ok := r.systems[key]
+	if !ok {
+		s = newSystems(conf, r.memory, r.group)
+		r.systems[key] = s
+	}
+	return s
+}
+
+// fingerprint returns the remote settings of this configuration, used to build the systems.
+func fingerprint(c Config) string {
+	return fmt.Sprintf("%q %q %q %q %d %g %d %s %q %s %q %p %t %q %q",
+		c.HostPatterns, c.InsecurePatterns, c.ProxyURLs, c.NoProxyPatterns, c.MaxRequests, c.RateLimit, c.Retries,
+		c.Timeout, c.CacheDir, c.CacheTTL, c.GoReleases, c.BasicAuth, c.VerifySum, c.SumDB, c.NoSumDBPatterns,
+	)
+}
+
+// directNamespace returns the settings changing the responses of the systems requested without Go module proxy:
+// the hosts with a known layout, the hosts allowed without TLS and the use of credentials.
+// The credentials themselves are not part of it, as it prefixes the keys of the file cache.
+func directNamespace(c Config) string {
+	var parts []string
+	if c.HostPatterns != "" {
+		parts = append(parts, "hosts="+c.HostPatterns)
+	}
+	if c.InsecurePatterns != "" {
+		parts = append(parts, "insecure="+c.InsecurePatterns)
+	}
+	if c.BasicAuth != nil {
+		parts = append(parts, "auth")
+	}
+	return cache.Key(parts...)
+}
+
+// systems are the version control systems used to check the dependencies, with the list of Go releases
+// and the checksum database, if the versions must be verified.
+type systems struct {
+	git, goGet, goProxy vcs.System
+	releases            func(ctx context.Context) (release.Releases, error)
+	cacheErr            error
+	sumDB               *sum.DB
+	sumErr              error
+}
+
+// newSystems returns the systems built with this configuration.
+// They share the same limiter, so the limits of the remote requests apply to all of them.
+// With a memory, it is used before the file cache and with a group, the requests in flight are shared.
+func newSystems(conf Config, memory *cache.Memory, group *flight.Group) *systems {
+	var (
+		s                     = &systems{}
+		hosts                 = vcs.NewHosts(conf.HostPatterns)
+		limiter               = vcs.NewLimiter(conf.MaxRequests, conf.RateLimit)
+		backoff               = vcs.Backoff{Attempts: conf.Retries + 1, Base: vcs.DefaultBackoff.Base, Max: vcs.DefaultBackoff.Max}
+		httpClient            = vcs.NewHTTPClient(conf.Timeout, conf.InsecurePatterns, vcs.WithLimiter(limiter))
+		gitVCS     vcs.System = git.New(httpClient, conf.BasicAuth, git.WithHosts(hosts), git.WithLimiter(limiter), git.WithBackoff(backoff))
+		proxyVCS   vcs.System = goproxy.New(httpClient, conf.BasicAuth, conf.ProxyURLs, conf.NoProxyPatterns)
+		hgVCS      vcs.System = hg.New(httpClient, conf.BasicAuth)
+		svnVCS     vcs.System = svn.New(httpClient, conf.BasicAuth)
+		direct                = directNamespace(conf)
+		opts       []goget.Option
+		stores     []vcs.Cache
+	)
+	if memory != nil {
+		stores = append(stores, memory)
+	}
+	if conf.CacheTTL > 0 {
+		store, err := cache.Open(conf.CacheDir, conf.CacheTTL)
+		if err == nil {
+			stores = append(stores, store)
+		} else {
+			s.cacheErr = err
+		}
+	}
+	if len(stores) > 0 {
+		store := cache.Chain(stores...)
+		// The versions listed depend on the proxies, or for the other systems, on the settings of the direct requests.
+		gitVCS = cache.New(git.Name, gitVCS, store, cache.WithNamespace(direct))
+		proxyVCS = cache.New(goproxy.Name, proxyVCS, store, cache.WithNamespace(conf.ProxyURLs))
+		hgVCS = cache.New(hg.Name, hgVCS, store, cache.WithNamespace(direct))
+		svnVCS = cache.New(svn.Name, svnVCS, store, cache.WithNamespace(direct))
+		opts = append(opts, goget.WithCache(store), goget.WithNamespace(direct))
+	}
+	if group != nil {
+		gitVCS = flight.New(git.Name, gitVCS, group, flight.WithNamespace(direct))
+		proxyVCS = flight.New(goproxy.Name, proxyVCS, group, flight.WithNamespace(conf.ProxyURLs))
+		hgVCS = flight.New(hg.Name, hgVCS, group, flight.WithNamespace(direct))
+		svnVCS = flight.New(svn.Name, svnVCS, group, flight.WithNamespace(direct))
+	}
+	opts = append(opts,
+		goget.WithBackoff(backoff),
+		goget.WithHosts(hosts),
+		// The go-import metadata can declare a Go module proxy or another VCS than git.
+		goget.WithSystem(goproxy.Name, proxyVCS),
+		goget.WithSystem(hg.Name, hgVCS),
+		goget.WithSystem(svn.Name, svnVCS),
+	)
+	s.git = gitVCS
+	s.goGet = goget.New(httpClient, gitVCS, opts...)
+	if group != nil {
+		s.goGet = flight.New(goget.Name, s.goGet, group, flight.WithNamespace(direct))
+	}
+	s.goProxy = proxyVCS
+	if conf.VerifySum {
+		s.sumDB, s.sumErr = sum.NewDB(httpClient, conf.BasicAuth, conf.SumDB, conf.NoSumDBPatterns)
+	}
+	s.releases = memoize(func(ctx context.Context) (release.Releases, error) {
+		rawURL := conf.GoReleases
+		if rawURL == "" {
+			rawURL = release.DefaultURL
+		}
+		return release.Fetch(ctx, httpClient, conf.BasicAuth, rawURL)
+	})
+	return s
+}
+
+// memoize memoizes the list of Go releases, or the failure to fetch it, for the rest of the run.
+// Only a failure due to the context of the caller is not kept, as the other callers may have more time.
+func memoize(fetch func(ctx context.Context) (release.Releases, error)) func(ctx context.Context) (release.Releases, error) {
+	var (
+		mu   sync.Mutex
+		done bool
+		res  release.Releases
+		err  error
+	)
+	return func(ctx context.Context) (release.Releases, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if done {
+			return res, err
+		}
+		rs, e := fetch(ctx)
+		if e != nil && ctx.Err() != nil {
+			return nil, e
+		}
+		done, res, err = true, rs, e
+		return res, err
+	}
+}
@@ -0,0 +1,103 @@
+// Copyright (c) 2020 Hervé Gouchet. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package goup
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/matryer/is"
+	errup "github.com/rvflash/goup/internal/errors"
+	"github.com/rvflash/goup/internal/netrc"
+	"github.com/rvflash/goup/internal/release"
+)
+
+func TestResolver_Get(t *testing.T) {
+	t.Parallel()
+	var (
+		are  = is.New(t)
+		r    = newResolver()
+		conf = Config{Timeout: time.Second}
+		s    = r.get(conf)
+	)
+	are.True(s.git != nil && s.goGet != nil && s.goProxy != nil && s.releases != nil) // missing system
+	// The settings not related to the remotes do not matter.
+	are.True(r.get(Config{Timeout: time.Second, Major: true, Strict: true}) == s) // expected same systems
+	are.True(r.get(Config{Timeout: time.Minute}) != s)                            // expected other systems
+	var n *resolver
+	are.True(n.get(conf) != n.get(conf)) // expected new systems without resolver
+}
+
+func TestDirectNamespace(t *testing.T) {
+	t.Parallel()
+	var (
+		are = is.New(t)
+		dt  = map[string]struct {
+			in  Config
+			out string
+		}{
+			"default":  {},
+			"hosts":    {in: Config{HostPatterns: "example.com/*"}, out: "hosts=example.com/*"},
+			"insecure": {in: Config{InsecurePatterns: "example.com"}, out: "insecure=example.com"},
+			"auth":     {in: Config{BasicAuth: netrc.File{}}, out: "auth"},
+			"all": {
+				in:  Config{HostPatterns: "example.com/*", InsecurePatterns: "example.com", BasicAuth: netrc.File{}},
+				out: "hosts=example.com/* insecure=example.com auth",
+			},
+		}
+	)
+	for name, ts := range dt {
+		tt := ts
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			are.Equal(directNamespace(tt.in), tt.out) // mismatch namespace
+		})
+	}
+}
+
+func TestMemoize(t *testing.T) {
+	t.Parallel()
+	var (
+		are   = is.New(t)
+		calls int
+		err   error = errup.ErrFetch
+		fetch       = memoize(func(ctx context.Context) (release.Releases, error) {
+			calls++
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			if err != nil {
+				return nil, err
+			}
+			return release.Releases{{Version: "go1.21.0", Stable: true}}, nil
+		})
+	)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, e := fetch(ctx)
+	are.True(errors.Is(e, context.Canceled)) // mismatch error
+	for i := 0; i < 2; i++ {
+		_, e = fetch(context.Background())
+		are.Equal(e, errup.ErrFetch) // mismatch error
+	}
+	err = nil
+	_, e = fetch(context.Background())
+	are.Equal(e, errup.ErrFetch) // the failure must be kept
+	are.Equal(calls, 2)          // only the failure of the context must not be kept
+
+	calls = 0
+	fetch = memoize(func(context.Context) (release.Releases, error) {
+		calls++
+		return release.Releases{{Version: "go1.21.0", Stable: true}}, nil
+	})
+	for i := 0; i < 2; i++ {
+		rs, e := fetch(context.Background())
+		are.NoErr(e)          // unexpected error
+		are.Equal(len(rs), 1) // mismatch releases
+	}
+	are.Equal(calls, 1) // mismatch calls
+}
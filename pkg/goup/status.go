@@ -0,0 +1,61 @@
+// Copyright (c) 2020 Hervé Gouchet. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package goup
+
+// Status defines the result of the check of a dependency.
+type Status uint8
+
+// List of available statuses.
+const (
+	// Unknown is the zero value, used when the message does not concern the check of a dependency.
+	Unknown Status = iota
+	// Failed is used when the check failed.
+	Failed
+	// Outdated is used when a newer version is available.
+	Outdated
+	// Skipped is used when the check has not been done.
+	Skipped
+	// UpToDate is used when the dependency uses the expected version.
+	UpToDate
+	// Updated is used when the dependency is updated in the go.mod file.
+	Updated
+	// Aborted is used when the check has been stopped by the strict mode.
+	Aborted
+	// Retracted is used when the dependency uses a version retracted by its authors.
+	Retracted
+	// Deprecated is used when the dependency is deprecated by its authors.
+	Deprecated
+	// Drifted is used when the dependency is required at different versions by the modules of a workspace.
+	Drifted
+	// MajorAvailable is used when a newer major version of the dependency is published under a new module path.
+	MajorAvailable
+)
+
+var statuses = [...]string{
+	Unknown:        "unknown",
+	Failed:         "failed",
+	Outdated:       "outdated",
+	Skipped:        "skipped",
+	UpToDate:       "up-to-date",
+	Updated:        "updated",
+	Aborted:        "aborted",
+	Retracted:      "retracted",
+	Deprecated:     "deprecated",
+	Drifted:        "drifted",
+	MajorAvailable: "major-available",
+}
+
+// String implements the fmt.Stringer interface.
+func (s Status) String() string {
+	if int(s) < len(statuses) {
+		return statuses[s]
+	}
+	return ""
+}
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (s Status) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
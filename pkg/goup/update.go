@@ -0,0 +1,31 @@
+// Copyright (c) 2020 Hervé Gouchet. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package goup
+
+import "github.com/rvflash/goup/internal/semver"
+
+// UpdateKind defines the kind of update proposed for a dependency.
+type UpdateKind string
+
+// List of available kinds of update.
+const (
+	// NoUpdate is used when no newer version is proposed.
+	NoUpdate UpdateKind = ""
+	// PatchUpdate proposes a newer patch version.
+	PatchUpdate UpdateKind = semver.Patch
+	// MinorUpdate proposes a newer minor version.
+	MinorUpdate UpdateKind = semver.Minor
+	// MajorUpdate proposes a newer major version.
+	MajorUpdate UpdateKind = semver.Major
+	// PrereleaseUpdate proposes a prerelease.
+	PrereleaseUpdate UpdateKind = semver.Prerelease
+	// PseudoUpdate proposes a pseudo-version, as a commit without tag.
+	PseudoUpdate UpdateKind = semver.Pseudo
+)
+
+// String implements the fmt.Stringer interface.
+func (k UpdateKind) String() string {
+	return string(k)
+}
@@ -14,6 +14,7 @@ import (
 	"github.com/rvflash/goup/internal/semver"
 
 	"golang.org/x/mod/modfile"
+	modv "golang.org/x/mod/module"
 )
 
 //go:generate mockgen -destination ../../testdata/mock/mod/file.go -source file.go
@@ -32,12 +33,25 @@ type Mod interface {
 	Name() string
 	// Dependencies returns the dependencies of the module.
 	Dependencies() []Module
+	// Go returns the Go version declared by the go directive, if any.
+	Go() string
+	// Toolchain returns the toolchain declared by the toolchain directive, if any.
+	Toolchain() string
 	// UpdateRequire adds an update of this required module path to the given version.
 	UpdateRequire(path, version string) error
+	// MoveRequire replaces the requirement of this module path by the one of the new path at the given version,
+	// like a new major version of the module.
+	MoveRequire(oldPath, newPath, version string) error
 	// UpdateReplace adds an update on the replacement of this module path to the given version.
 	UpdateReplace(oldPath, newVersion string) error
+	// UpdateGo adds an update of the go directive to the given version.
+	UpdateGo(version string) error
+	// UpdateToolchain adds an update of the toolchain directive to the given name.
+	UpdateToolchain(name string) error
 	// Format applies any requested updates to the file content.
 	Format() ([]byte, error)
+	// Changes returns the module versions used to build the module added by the updates, and those no longer used.
+	Changes() (added, removed []modv.Version)
 }
 
 // Parse tries to open a go.mod file.
@@ -54,14 +68,16 @@ func Parse(path string) (*File, error) {
 		return nil, fmt.Errorf("%w: %s", errors.ErrMod, err.Error())
 	}
 	return &File{
-		raw:  f,
-		mods: dependencies(f),
+		raw:      f,
+		mods:     dependencies(f),
+		versions: buildVersions(f),
 	}, nil
 }
 
 // File is a go.mod file.
 type File struct {
-	mods []Module
+	mods     []Module
+	versions []modv.Version
 
 	mu      sync.RWMutex
 	raw     *modfile.File
@@ -95,6 +111,55 @@ func (f *File) Name() string {
 	return f.raw.Syntax.Name
 }
 
+// Go implements the Mod interface.
+func (f *File) Go() string {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	if f.raw == nil || f.raw.Go == nil {
+		return ""
+	}
+	return f.raw.Go.Version
+}
+
+// Toolchain implements the Mod interface.
+func (f *File) Toolchain() string {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	if f.raw == nil || f.raw.Toolchain == nil {
+		return ""
+	}
+	return f.raw.Toolchain.Name
+}
+
+// Line returns the line number of the replace or require statement of this module path.
+// Replace statements are matched by their new path first, as used by the dependencies.
+// It returns 0 if the module path is not found.
+func (f *File) Line(path string) int {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	if f.raw == nil {
+		return 0
+	}
+	for _, r := range f.raw.Replace {
+		if r.New.Path == path && r.Syntax != nil {
+			return r.Syntax.Start.Line
+		}
+	}
+	for _, r := range f.raw.Require {
+		if r.Mod.Path == path && r.Syntax != nil {
+			return r.Syntax.Start.Line
+		}
+	}
+	// The go and toolchain directives are located by their name.
+	switch {
+	case path == "go" && f.raw.Go != nil && f.raw.Go.Syntax != nil:
+		return f.raw.Go.Syntax.Start.Line
+	case path == "toolchain" && f.raw.Toolchain != nil && f.raw.Toolchain.Syntax != nil:
+		return f.raw.Toolchain.Syntax.Start.Line
+	}
+	return 0
+}
+
 // UpdateRequire implements the Mod interface.
 func (f *File) UpdateRequire(path, version string) error {
 	f.mu.RLock()
@@ -106,6 +171,50 @@ func (f *File) UpdateRequire(path, version string) error {
 	return f.raw.AddRequire(path, version)
 }
 
+// MoveRequire implements the Mod interface.
+// The requirement is rewritten in place, so it keeps its position and its comments, like indirect.
+// If the new path is already required, the old requirement is dropped and the new one updated.
+func (f *File) MoveRequire(oldPath, newPath, version string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.raw == nil {
+		return errors.ErrMod
+	}
+	var found, exists bool
+	for _, r := range f.raw.Require {
+		found = found || r.Mod.Path == oldPath
+		exists = exists || r.Mod.Path == newPath
+	}
+	if !found {
+		return errors.ErrMissing
+	}
+	f.updated = true
+	if exists {
+		if err := f.raw.DropRequire(oldPath); err != nil {
+			return err
+		}
+		return f.raw.AddRequire(newPath, version)
+	}
+	for _, r := range f.raw.Require {
+		if r.Mod.Path != oldPath || r.Syntax == nil {
+			continue
+		}
+		// A require line in a block has no verb.
+		tok := r.Syntax.Token
+		if len(tok) > 0 && tok[0] == requireVerb {
+			tok = tok[1:]
+		}
+		if len(tok) < 2 {
+			return errors.ErrMod
+		}
+		tok[0], tok[1] = modfile.AutoQuote(newPath), version
+		r.Mod = modv.Version{Path: newPath, Version: version}
+	}
+	return nil
+}
+
+const requireVerb = "require"
+
 // UpdateReplace implements the Mod interface.
 func (f *File) UpdateReplace(oldPath, newVersion string) error {
 	f.mu.RLock()
@@ -118,6 +227,28 @@ func (f *File) UpdateReplace(oldPath, newVersion string) error {
 	return f.raw.AddReplace(oldPath, "", newPath, newVersion)
 }
 
+// UpdateGo implements the Mod interface.
+func (f *File) UpdateGo(version string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.raw == nil {
+		return errors.ErrMod
+	}
+	f.updated = true
+	return f.raw.AddGoStmt(version)
+}
+
+// UpdateToolchain implements the Mod interface.
+func (f *File) UpdateToolchain(name string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.raw == nil {
+		return errors.ErrMod
+	}
+	f.updated = true
+	return f.raw.AddToolchainStmt(name)
+}
+
 func findNewPath(f *modfile.File, oldPath string) (string, error) {
 	if f == nil {
 		return "", errors.ErrMod
@@ -147,6 +278,61 @@ func (f *File) Format() ([]byte, error) {
 	return buf, nil
 }
 
+// Changes implements the Mod interface.
+func (f *File) Changes() (added, removed []modv.Version) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	if f.raw == nil {
+		return nil, nil
+	}
+	return diff(f.versions, buildVersions(f.raw))
+}
+
+// buildVersions returns the module versions used to build the module: those required,
+// or those replacing them. The replacements by a local directory have no version, so they are ignored.
+func buildVersions(f *modfile.File) []modv.Version {
+	var res []modv.Version
+	for _, r := range f.Require {
+		v := r.Mod
+		if v.Path == "" {
+			// Dropped requirement.
+			continue
+		}
+		for _, p := range f.Replace {
+			if p.Old.Path == v.Path && (p.Old.Version == "" || p.Old.Version == v.Version) {
+				v = p.New
+			}
+		}
+		if v.Version != "" {
+			res = append(res, v)
+		}
+	}
+	return res
+}
+
+// diff returns the versions of the new list missing in the old one, and those of the old list missing in the new one.
+func diff(old, cur []modv.Version) (added, removed []modv.Version) {
+	in := func(list []modv.Version, v modv.Version) bool {
+		for _, w := range list {
+			if w == v {
+				return true
+			}
+		}
+		return false
+	}
+	for _, v := range cur {
+		if !in(old, v) {
+			added = append(added, v)
+		}
+	}
+	for _, v := range old {
+		if !in(cur, v) {
+			removed = append(removed, v)
+		}
+	}
+	return added, removed
+}
+
 // dependencies returns the list of modules in this go.mod file.
 // Firstly we get the modules used to replace legacy ones.
 // Then those required. We use the `replace` dependency instead of this required.
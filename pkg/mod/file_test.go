@@ -15,6 +15,8 @@ import (
 	"github.com/matryer/is"
 	errup "github.com/rvflash/goup/internal/errors"
 	"github.com/rvflash/goup/pkg/mod"
+
+	"golang.org/x/mod/module"
 )
 
 const (
@@ -46,6 +48,47 @@ func TestFile_Module(t *testing.T) {
 	is.New(t).Equal(f.Module(), "")
 }
 
+func TestFile_Line(t *testing.T) {
+	t.Parallel()
+	var (
+		f   mod.File
+		are = is.New(t)
+	)
+	are.Equal(f.Line(d0), 0) // mismatch default
+	out, err := mod.Parse(filepath.Join(updateGoMod...))
+	are.NoErr(err)                                     // parse error
+	are.Equal(out.Line(d1), 5)                         // mismatch require
+	are.Equal(out.Line("github.com/notme/elapsed"), 8) // mismatch replace
+	are.Equal(out.Line(d3), 0)                         // mismatch unknown
+	are.Equal(out.Line("go"), 10)                      // mismatch go directive
+	are.Equal(out.Line("toolchain"), 0)                // mismatch missing toolchain
+}
+
+func TestFile_Directives(t *testing.T) {
+	t.Parallel()
+	var (
+		f   mod.File
+		are = is.New(t)
+	)
+	are.Equal(f.Go(), "")                                  // mismatch default go
+	are.Equal(f.Toolchain(), "")                           // mismatch default toolchain
+	are.Equal(f.UpdateGo("1.22.0"), errup.ErrMod)          // mismatch default go update
+	are.Equal(f.UpdateToolchain("go1.22.0"), errup.ErrMod) // mismatch default toolchain update
+
+	out, err := mod.Parse(filepath.Join(toolchainGoMod...))
+	are.NoErr(err)                             // parse error
+	are.Equal(out.Go(), "1.21")                // mismatch go
+	are.Equal(out.Toolchain(), "go1.21.1")     // mismatch toolchain
+	are.Equal(out.Line("toolchain"), 5)        // mismatch toolchain line
+	are.NoErr(out.UpdateGo("1.22.0"))          // update go
+	are.NoErr(out.UpdateToolchain("go1.22.1")) // update toolchain
+	are.Equal(out.Go(), "1.22.0")              // mismatch updated go
+	are.Equal(out.Toolchain(), "go1.22.1")     // mismatch updated toolchain
+	buf, err := out.Format()
+	are.NoErr(err)                                                                                // expected changes
+	are.Equal(string(buf), "module github.com/rvflash/goup\n\ngo 1.22.0\n\ntoolchain go1.22.1\n") // mismatch data
+}
+
 func TestFile_Format(t *testing.T) {
 	t.Parallel()
 	are := is.New(t)
@@ -87,6 +130,44 @@ func TestFile_UpdateRequire(t *testing.T) {
 	is.New(t).Equal(f.UpdateRequire(d0, v0), errup.ErrMod)
 }
 
+func TestFile_MoveRequire(t *testing.T) {
+	t.Parallel()
+	are := is.New(t)
+	var f mod.File
+	are.Equal(f.MoveRequire(d0, d0+"/v2", v1), errup.ErrMod) // mismatch default
+	out, err := mod.Parse(filepath.Join(validGoMod...))
+	are.NoErr(err)                                                           // unexpected error
+	are.True(errors.Is(out.MoveRequire(d0, d0+"/v2", v1), errup.ErrMissing)) // mismatch unknown path
+	const (
+		oldPath = "google.golang.org/appengine"
+		newPath = oldPath + "/v2"
+	)
+	are.NoErr(out.MoveRequire(oldPath, newPath, "v2.0.6")) // unexpected error
+	buf, err := out.Format()
+	are.NoErr(err)                                                              // unexpected error
+	are.True(!bytes.Contains(buf, []byte(oldPath+" v1.6.0")))                   // old requirement not dropped
+	are.True(bytes.Contains(buf, []byte("\t"+newPath+" v2.0.6 // indirect\n"))) // new requirement not added
+}
+
+func TestFile_Changes(t *testing.T) {
+	t.Parallel()
+	are := is.New(t)
+	var f mod.File
+	added, removed := f.Changes()
+	are.Equal(len(added)+len(removed), 0) // mismatch default
+	out, err := mod.Parse(filepath.Join(updateGoMod...))
+	are.NoErr(err) // unexpected error
+	added, removed = out.Changes()
+	are.Equal(len(added)+len(removed), 0) // unexpected changes
+	const elapsed = "github.com/notme/elapsed"
+	are.NoErr(out.UpdateRequire(d1, "v0.4.0")) // unexpected error
+	are.NoErr(out.UpdateReplace(d0, "v1.1.0")) // unexpected error
+	added, removed = out.Changes()
+	// The version of the replacement is used to build the module, not the required one.
+	are.Equal(added, []module.Version{{Path: elapsed, Version: "v1.1.0"}, {Path: d1, Version: "v0.4.0"}})   // mismatch added
+	are.Equal(removed, []module.Version{{Path: elapsed, Version: "v1.0.0"}, {Path: d1, Version: "v0.3.1"}}) // mismatch removed
+}
+
 func TestFile_UpdateReplace(t *testing.T) {
 	t.Parallel()
 	are := is.New(t)
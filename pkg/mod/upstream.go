@@ -0,0 +1,87 @@
+// Copyright (c) 2020 Hervé Gouchet. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package mod
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/rvflash/goup/internal/errors"
+	"github.com/rvflash/goup/internal/semver"
+
+	"golang.org/x/mod/modfile"
+	modv "golang.org/x/mod/module"
+)
+
+// Upstream represents the go.mod file published by a dependency, in its latest version.
+type Upstream struct {
+	// Path is the module path declared by the go.mod file, which may differ from the required one
+	// when the module has been migrated.
+	Path string
+	// Deprecated is the deprecation message of the module, often naming its replacement.
+	Deprecated string
+	// Retractions lists the versions retracted by the authors of the module.
+	Retractions []Retraction
+	// Requires lists the module versions required by the go.mod file.
+	Requires []modv.Version
+}
+
+// Retraction is a version, or a closed interval of versions, retracted by the authors of a module.
+type Retraction struct {
+	Low       string
+	High      string
+	Rationale string
+}
+
+// ParseUpstream parses the content of the go.mod file published by the module behind this path.
+func ParseUpstream(path string, data []byte) (*Upstream, error) {
+	f, err := modfile.ParseLax(path, data, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", errors.ErrMod, err.Error())
+	}
+	u := new(Upstream)
+	if f.Module != nil {
+		u.Path = f.Module.Mod.Path
+		u.Deprecated = f.Module.Deprecated
+	}
+	for _, r := range f.Retract {
+		u.Retractions = append(u.Retractions, Retraction{
+			Low:       r.Low,
+			High:      r.High,
+			Rationale: strings.Join(strings.Fields(r.Rationale), " "),
+		})
+	}
+	for _, r := range f.Require {
+		u.Requires = append(u.Requires, r.Mod)
+	}
+	return u, nil
+}
+
+// Retracted returns the retraction of this version, if any.
+func (u *Upstream) Retracted(v semver.Tag) (r Retraction, ok bool) {
+	if u == nil || v == nil || !v.IsValid() {
+		return
+	}
+	for _, r = range u.Retractions {
+		if semver.Compare(semver.New(r.Low), v) <= 0 && semver.Compare(v, semver.New(r.High)) <= 0 {
+			return r, true
+		}
+	}
+	return Retraction{}, false
+}
+
+// Allowed returns the versions not retracted.
+func (u *Upstream) Allowed(versions semver.Tags) semver.Tags {
+	if u == nil || len(u.Retractions) == 0 {
+		return versions
+	}
+	res := make(semver.Tags, 0, len(versions))
+	for _, v := range versions {
+		if _, ok := u.Retracted(v); !ok {
+			res = append(res, v)
+		}
+	}
+	return res
+}
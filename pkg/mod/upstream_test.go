@@ -0,0 +1,91 @@
+// Copyright (c) 2020 Hervé Gouchet. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package mod_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/matryer/is"
+	errup "github.com/rvflash/goup/internal/errors"
+	"github.com/rvflash/goup/internal/semver"
+	"github.com/rvflash/goup/pkg/mod"
+
+	"golang.org/x/mod/module"
+)
+
+const upstream = `// Deprecated: use example.com/goup instead.
+module github.com/rvflash/goup
+
+require golang.org/x/mod v0.4.0
+
+retract (
+	// Published too early.
+	v1.0.0
+	// Data race on the
+	// cache.
+	[v1.1.0, v1.1.5]
+)
+`
+
+func TestParseUpstream(t *testing.T) {
+	t.Parallel()
+	are := is.New(t)
+	_, err := mod.ParseUpstream(d3, []byte("module a\nmodule b\n"))
+	are.True(errors.Is(err, errup.ErrMod)) // mismatch error
+
+	u, err := mod.ParseUpstream(d3, []byte(upstream))
+	are.NoErr(err)                                           // unexpected error
+	are.Equal(u.Path, "github.com/rvflash/goup")             // mismatch module path
+	are.Equal(u.Deprecated, "use example.com/goup instead.") // mismatch deprecation
+	are.Equal(u.Retractions, []mod.Retraction{
+		{Low: "v1.0.0", High: "v1.0.0", Rationale: "Published too early."},
+		{Low: "v1.1.0", High: "v1.1.5", Rationale: "Data race on the cache."},
+	}) // mismatch retractions
+	are.Equal(u.Requires, []module.Version{{Path: "golang.org/x/mod", Version: "v0.4.0"}}) // mismatch requirements
+}
+
+func TestUpstream_Retracted(t *testing.T) {
+	t.Parallel()
+	u, err := mod.ParseUpstream(d3, []byte(upstream))
+	var (
+		are = is.New(t)
+		dt  = map[string]struct {
+			in        semver.Tag
+			rationale string
+			ok        bool
+		}{
+			"Default":  {},
+			"Allowed":  {in: semver.New("v1.0.1")},
+			"Version":  {in: semver.New("v1.0.0"), rationale: "Published too early.", ok: true},
+			"Interval": {in: semver.New("v1.1.2"), rationale: "Data race on the cache.", ok: true},
+			"Bound":    {in: semver.New("v1.1.5"), rationale: "Data race on the cache.", ok: true},
+			"Prefixed": {in: semver.New("sub/v1.1.0"), rationale: "Data race on the cache.", ok: true},
+		}
+	)
+	are.NoErr(err) // unexpected error
+	for name, ts := range dt {
+		tt := ts
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			r, ok := u.Retracted(tt.in)
+			are.Equal(ok, tt.ok)                 // mismatch result
+			are.Equal(r.Rationale, tt.rationale) // mismatch rationale
+		})
+	}
+}
+
+func TestUpstream_Allowed(t *testing.T) {
+	t.Parallel()
+	var (
+		are = is.New(t)
+		in  = semver.Tags{semver.New("v1.0.0"), semver.New("v1.0.1"), semver.New("v1.1.1"), semver.New("v1.2.0")}
+		u   *mod.Upstream
+	)
+	are.Equal(u.Allowed(in), in) // mismatch default
+	u, err := mod.ParseUpstream(d3, []byte(upstream))
+	are.NoErr(err)                                                                    // unexpected error
+	are.Equal(u.Allowed(in), semver.Tags{semver.New("v1.0.1"), semver.New("v1.2.0")}) // mismatch result
+}
@@ -0,0 +1,226 @@
+// Copyright (c) 2020 Hervé Gouchet. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package mod
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/rvflash/goup/internal/errors"
+	"github.com/rvflash/goup/internal/semver"
+
+	"golang.org/x/mod/modfile"
+	modv "golang.org/x/mod/module"
+)
+
+// WorkFilename is the name of Go workspace file.
+const WorkFilename = "go.work"
+
+// WorkParser defined the interface used to parse a go.work file.
+type WorkParser func(path string) (*Work, error)
+
+// ParseWork tries to open a go.work file.
+func ParseWork(path string) (*Work, error) {
+	if filepath.Base(path) != WorkFilename {
+		return nil, errors.ErrMod
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", errors.ErrMod, err.Error())
+	}
+	f, err := modfile.ParseWork(path, b, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", errors.ErrMod, err.Error())
+	}
+	return &Work{
+		raw:  f,
+		mods: replacements(f.Replace),
+	}, nil
+}
+
+// Work is a go.work file.
+// As a Mod, only its replace, go and toolchain directives are checked.
+type Work struct {
+	mods []Module
+
+	mu      sync.RWMutex
+	raw     *modfile.WorkFile
+	updated bool
+}
+
+// Uses returns the paths of the go.mod files of the modules used by the workspace.
+func (w *Work) Uses() []string {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	if w.raw == nil || w.raw.Syntax == nil {
+		return nil
+	}
+	var (
+		dir = filepath.Dir(w.raw.Syntax.Name)
+		res = make([]string, 0, len(w.raw.Use))
+	)
+	for _, u := range w.raw.Use {
+		p := filepath.FromSlash(u.Path)
+		if !filepath.IsAbs(p) {
+			p = filepath.Join(dir, p)
+		}
+		res = append(res, filepath.Join(p, Filename))
+	}
+	return res
+}
+
+// Dependencies implements the Mod interface.
+func (w *Work) Dependencies() []Module {
+	return w.mods
+}
+
+// Module implements the Mod interface.
+// A workspace has no module path, its name is used instead.
+func (w *Work) Module() string {
+	return w.Name()
+}
+
+// Name implements the Mod interface.
+func (w *Work) Name() string {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	if w.raw == nil || w.raw.Syntax == nil {
+		// Avoids panic.
+		return ""
+	}
+	return w.raw.Syntax.Name
+}
+
+// Go implements the Mod interface.
+func (w *Work) Go() string {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	if w.raw == nil || w.raw.Go == nil {
+		return ""
+	}
+	return w.raw.Go.Version
+}
+
+// Toolchain implements the Mod interface.
+func (w *Work) Toolchain() string {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	if w.raw == nil || w.raw.Toolchain == nil {
+		return ""
+	}
+	return w.raw.Toolchain.Name
+}
+
+// Line returns the line number of the replace statement of this module path,
+// or of the go and toolchain directives by their name. It returns 0 if the module path is not found.
+func (w *Work) Line(path string) int {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	if w.raw == nil {
+		return 0
+	}
+	for _, r := range w.raw.Replace {
+		if r.New.Path == path && r.Syntax != nil {
+			return r.Syntax.Start.Line
+		}
+	}
+	switch {
+	case path == "go" && w.raw.Go != nil && w.raw.Go.Syntax != nil:
+		return w.raw.Go.Syntax.Start.Line
+	case path == "toolchain" && w.raw.Toolchain != nil && w.raw.Toolchain.Syntax != nil:
+		return w.raw.Toolchain.Syntax.Start.Line
+	}
+	return 0
+}
+
+// UpdateRequire implements the Mod interface.
+// A workspace has no require statement.
+func (w *Work) UpdateRequire(_, _ string) error {
+	return errors.ErrMissing
+}
+
+// MoveRequire implements the Mod interface.
+// A workspace has no require statement.
+func (w *Work) MoveRequire(_, _, _ string) error {
+	return errors.ErrMissing
+}
+
+// Changes implements the Mod interface.
+// The go.work.sum file of a workspace is left to the go command.
+func (w *Work) Changes() (added, removed []modv.Version) {
+	return nil, nil
+}
+
+// UpdateReplace implements the Mod interface.
+func (w *Work) UpdateReplace(oldPath, newVersion string) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.raw == nil {
+		return errors.ErrMod
+	}
+	for _, r := range w.raw.Replace {
+		// The dependencies are known by the path of the replacement.
+		if r.Old.Path == oldPath || r.New.Path == oldPath {
+			w.updated = true
+			return w.raw.AddReplace(r.Old.Path, r.Old.Version, r.New.Path, newVersion)
+		}
+	}
+	return errors.ErrMissing
+}
+
+// UpdateGo implements the Mod interface.
+func (w *Work) UpdateGo(version string) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.raw == nil {
+		return errors.ErrMod
+	}
+	w.updated = true
+	return w.raw.AddGoStmt(version)
+}
+
+// UpdateToolchain implements the Mod interface.
+func (w *Work) UpdateToolchain(name string) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.raw == nil {
+		return errors.ErrMod
+	}
+	w.updated = true
+	return w.raw.AddToolchainStmt(name)
+}
+
+// Format implements the Mod interface.
+func (w *Work) Format() ([]byte, error) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	if w.raw == nil {
+		return nil, errors.ErrMod
+	}
+	buf := modfile.Format(w.raw.Syntax)
+	if !w.updated {
+		return buf, errors.ErrNotModified
+	}
+	return buf, nil
+}
+
+// replacements returns the modules used to replace legacy ones.
+// As with the go.mod files, the local replacements are ignored.
+func replacements(list []*modfile.Replace) []Module {
+	var res []Module
+	for _, r := range list {
+		if modfile.IsDirectoryPath(r.New.Path) {
+			continue
+		}
+		res = append(res, &module{
+			path:        r.New.Path,
+			replacement: true,
+			version:     semver.New(r.New.Version),
+		})
+	}
+	return res
+}
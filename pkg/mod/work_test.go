@@ -0,0 +1,80 @@
+// Copyright (c) 2020 Hervé Gouchet. All rights reserved.
+// Use of this source code is governed by the MIT License
+// that can be found in the LICENSE file.
+
+package mod_test
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/matryer/is"
+	errup "github.com/rvflash/goup/internal/errors"
+	"github.com/rvflash/goup/pkg/mod"
+)
+
+var workDir = []string{"..", "..", "testdata", "golden", "work"}
+
+func TestParseWork(t *testing.T) {
+	t.Parallel()
+	var (
+		are = is.New(t)
+		dt  = map[string]struct {
+			in  string
+			err error
+		}{
+			"default":      {err: errup.ErrMod},
+			"invalid name": {in: filepath.Join(validGoMod...), err: errup.ErrMod},
+			"not found":    {in: filepath.Join("testdata", mod.WorkFilename), err: errup.ErrMod},
+			"ok":           {in: filepath.Join(append(workDir, mod.WorkFilename)...)},
+		}
+	)
+	for name, ts := range dt {
+		tt := ts
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			_, err := mod.ParseWork(tt.in)
+			are.True(errors.Is(err, tt.err)) // mismatch error
+		})
+	}
+}
+
+func TestWork(t *testing.T) {
+	t.Parallel()
+	var (
+		w   mod.Work
+		are = is.New(t)
+	)
+	are.Equal(w.Name(), "")                          // mismatch default name
+	are.Equal(w.Uses(), nil)                         // mismatch default uses
+	are.Equal(w.Line(d0), 0)                         // mismatch default line
+	are.Equal(w.UpdateReplace(d0, v0), errup.ErrMod) // mismatch default update
+	_, err := w.Format()
+	are.Equal(err, errup.ErrMod) // mismatch default format
+
+	name := filepath.Join(append(workDir, mod.WorkFilename)...)
+	out, err := mod.ParseWork(name)
+	are.NoErr(err)                 // parse error
+	are.Equal(out.Module(), name)  // mismatch module
+	are.Equal(out.Go(), "1.21")    // mismatch go
+	are.Equal(out.Toolchain(), "") // mismatch toolchain
+	are.Equal(out.Uses(), []string{
+		filepath.Join(append(workDir, "a", mod.Filename)...),
+		filepath.Join(append(workDir, "b", mod.Filename)...),
+	}) // mismatch uses
+	deps := out.Dependencies()
+	are.Equal(len(deps), 1)                                          // mismatch number of dependencies
+	are.Equal(deps[0].Path(), "github.com/notme/elapsed")            // mismatch path
+	are.True(deps[0].Replacement())                                  // expected replacement
+	are.Equal(out.Line("github.com/notme/elapsed"), 8)               // mismatch line
+	are.True(errors.Is(out.UpdateRequire(d1, v1), errup.ErrMissing)) // mismatch require
+	are.True(errors.Is(out.UpdateReplace(d1, v1), errup.ErrMissing)) // mismatch unknown replace
+	_, err = out.Format()
+	are.True(errors.Is(err, errup.ErrNotModified))               // expected no change
+	are.NoErr(out.UpdateReplace("github.com/notme/elapsed", v0)) // update replace
+	buf, err := out.Format()
+	are.NoErr(err)                                                                  // expected changes
+	are.True(strings.Contains(string(buf), "=> github.com/notme/elapsed "+v0+"\n")) // mismatch data
+}
@@ -0,0 +1,8 @@
+module example.com/work/a
+
+go 1.21
+
+require (
+	github.com/matryer/is v1.2.0
+	github.com/rvflash/backoff v0.3.1
+)
@@ -0,0 +1,8 @@
+module example.com/work/b
+
+go 1.21
+
+require (
+	github.com/matryer/is v1.4.1
+	github.com/rvflash/backoff v0.3.1
+)
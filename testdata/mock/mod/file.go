@@ -14,6 +14,7 @@ import (
 
 	mod "github.com/rvflash/goup/pkg/mod"
 	gomock "go.uber.org/mock/gomock"
+	module "golang.org/x/mod/module"
 )
 
 // MockMod is a mock of Mod interface.
@@ -68,6 +69,20 @@ func (mr *MockModMockRecorder) Format() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Format", reflect.TypeOf((*MockMod)(nil).Format))
 }
 
+// Go mocks base method.
+func (m *MockMod) Go() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Go")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// Go indicates an expected call of Go.
+func (mr *MockModMockRecorder) Go() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Go", reflect.TypeOf((*MockMod)(nil).Go))
+}
+
 // Module mocks base method.
 func (m *MockMod) Module() string {
 	m.ctrl.T.Helper()
@@ -96,6 +111,34 @@ func (mr *MockModMockRecorder) Name() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Name", reflect.TypeOf((*MockMod)(nil).Name))
 }
 
+// Toolchain mocks base method.
+func (m *MockMod) Toolchain() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Toolchain")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// Toolchain indicates an expected call of Toolchain.
+func (mr *MockModMockRecorder) Toolchain() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Toolchain", reflect.TypeOf((*MockMod)(nil).Toolchain))
+}
+
+// UpdateGo mocks base method.
+func (m *MockMod) UpdateGo(version string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateGo", version)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateGo indicates an expected call of UpdateGo.
+func (mr *MockModMockRecorder) UpdateGo(version any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateGo", reflect.TypeOf((*MockMod)(nil).UpdateGo), version)
+}
+
 // UpdateReplace mocks base method.
 func (m *MockMod) UpdateReplace(oldPath, newVersion string) error {
 	m.ctrl.T.Helper()
@@ -123,3 +166,46 @@ func (mr *MockModMockRecorder) UpdateRequire(path, version any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateRequire", reflect.TypeOf((*MockMod)(nil).UpdateRequire), path, version)
 }
+
+// Changes mocks base method.
+func (m *MockMod) Changes() ([]module.Version, []module.Version) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Changes")
+	ret0, _ := ret[0].([]module.Version)
+	ret1, _ := ret[1].([]module.Version)
+	return ret0, ret1
+}
+
+// Changes indicates an expected call of Changes.
+func (mr *MockModMockRecorder) Changes() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Changes", reflect.TypeOf((*MockMod)(nil).Changes))
+}
+
+// MoveRequire mocks base method.
+func (m *MockMod) MoveRequire(oldPath, newPath, version string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MoveRequire", oldPath, newPath, version)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MoveRequire indicates an expected call of MoveRequire.
+func (mr *MockModMockRecorder) MoveRequire(oldPath, newPath, version any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MoveRequire", reflect.TypeOf((*MockMod)(nil).MoveRequire), oldPath, newPath, version)
+}
+
+// UpdateToolchain mocks base method.
+func (m *MockMod) UpdateToolchain(name string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateToolchain", name)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateToolchain indicates an expected call of UpdateToolchain.
+func (mr *MockModMockRecorder) UpdateToolchain(name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateToolchain", reflect.TypeOf((*MockMod)(nil).UpdateToolchain), name)
+}
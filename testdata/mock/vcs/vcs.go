@@ -86,6 +86,201 @@ func (mr *MockSystemMockRecorder) FetchURL(ctx, url any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchURL", reflect.TypeOf((*MockSystem)(nil).FetchURL), ctx, url)
 }
 
+// MockModFetcher is a mock of ModFetcher interface.
+type MockModFetcher struct {
+	ctrl     *gomock.Controller
+	recorder *MockModFetcherMockRecorder
+}
+
+// MockModFetcherMockRecorder is the mock recorder for MockModFetcher.
+type MockModFetcherMockRecorder struct {
+	mock *MockModFetcher
+}
+
+// NewMockModFetcher creates a new mock instance.
+func NewMockModFetcher(ctrl *gomock.Controller) *MockModFetcher {
+	mock := &MockModFetcher{ctrl: ctrl}
+	mock.recorder = &MockModFetcherMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockModFetcher) EXPECT() *MockModFetcherMockRecorder {
+	return m.recorder
+}
+
+// FetchMod mocks base method.
+func (m *MockModFetcher) FetchMod(ctx context.Context, path, version string) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FetchMod", ctx, path, version)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FetchMod indicates an expected call of FetchMod.
+func (mr *MockModFetcherMockRecorder) FetchMod(ctx, path, version any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchMod", reflect.TypeOf((*MockModFetcher)(nil).FetchMod), ctx, path, version)
+}
+
+// FetchModURL mocks base method.
+func (m *MockModFetcher) FetchModURL(ctx context.Context, url, dir, version string) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FetchModURL", ctx, url, dir, version)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FetchModURL indicates an expected call of FetchModURL.
+func (mr *MockModFetcherMockRecorder) FetchModURL(ctx, url, dir, version any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchModURL", reflect.TypeOf((*MockModFetcher)(nil).FetchModURL), ctx, url, dir, version)
+}
+
+// MockHeadFetcher is a mock of HeadFetcher interface.
+type MockHeadFetcher struct {
+	ctrl     *gomock.Controller
+	recorder *MockHeadFetcherMockRecorder
+}
+
+// MockHeadFetcherMockRecorder is the mock recorder for MockHeadFetcher.
+type MockHeadFetcherMockRecorder struct {
+	mock *MockHeadFetcher
+}
+
+// NewMockHeadFetcher creates a new mock instance.
+func NewMockHeadFetcher(ctrl *gomock.Controller) *MockHeadFetcher {
+	mock := &MockHeadFetcher{ctrl: ctrl}
+	mock.recorder = &MockHeadFetcherMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockHeadFetcher) EXPECT() *MockHeadFetcherMockRecorder {
+	return m.recorder
+}
+
+// FetchHead mocks base method.
+func (m *MockHeadFetcher) FetchHead(ctx context.Context, path string) (*vcs.Revision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FetchHead", ctx, path)
+	ret0, _ := ret[0].(*vcs.Revision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FetchHead indicates an expected call of FetchHead.
+func (mr *MockHeadFetcherMockRecorder) FetchHead(ctx, path any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchHead", reflect.TypeOf((*MockHeadFetcher)(nil).FetchHead), ctx, path)
+}
+
+// FetchHeadURL mocks base method.
+func (m *MockHeadFetcher) FetchHeadURL(ctx context.Context, url string) (*vcs.Revision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FetchHeadURL", ctx, url)
+	ret0, _ := ret[0].(*vcs.Revision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FetchHeadURL indicates an expected call of FetchHeadURL.
+func (mr *MockHeadFetcherMockRecorder) FetchHeadURL(ctx, url any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchHeadURL", reflect.TypeOf((*MockHeadFetcher)(nil).FetchHeadURL), ctx, url)
+}
+
+// MockZipFetcher is a mock of ZipFetcher interface.
+type MockZipFetcher struct {
+	ctrl     *gomock.Controller
+	recorder *MockZipFetcherMockRecorder
+}
+
+// MockZipFetcherMockRecorder is the mock recorder for MockZipFetcher.
+type MockZipFetcherMockRecorder struct {
+	mock *MockZipFetcher
+}
+
+// NewMockZipFetcher creates a new mock instance.
+func NewMockZipFetcher(ctrl *gomock.Controller) *MockZipFetcher {
+	mock := &MockZipFetcher{ctrl: ctrl}
+	mock.recorder = &MockZipFetcherMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockZipFetcher) EXPECT() *MockZipFetcherMockRecorder {
+	return m.recorder
+}
+
+// FetchZip mocks base method.
+func (m *MockZipFetcher) FetchZip(ctx context.Context, path, version string) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FetchZip", ctx, path, version)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FetchZip indicates an expected call of FetchZip.
+func (mr *MockZipFetcherMockRecorder) FetchZip(ctx, path, version any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchZip", reflect.TypeOf((*MockZipFetcher)(nil).FetchZip), ctx, path, version)
+}
+
+// MockCache is a mock of Cache interface.
+type MockCache struct {
+	ctrl     *gomock.Controller
+	recorder *MockCacheMockRecorder
+}
+
+// MockCacheMockRecorder is the mock recorder for MockCache.
+type MockCacheMockRecorder struct {
+	mock *MockCache
+}
+
+// NewMockCache creates a new mock instance.
+func NewMockCache(ctrl *gomock.Controller) *MockCache {
+	mock := &MockCache{ctrl: ctrl}
+	mock.recorder = &MockCacheMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCache) EXPECT() *MockCacheMockRecorder {
+	return m.recorder
+}
+
+// Get mocks base method.
+func (m *MockCache) Get(key string, v any) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", key, v)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockCacheMockRecorder) Get(key, v any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockCache)(nil).Get), key, v)
+}
+
+// Set mocks base method.
+func (m *MockCache) Set(key string, v any) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Set", key, v)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Set indicates an expected call of Set.
+func (mr *MockCacheMockRecorder) Set(key, v any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Set", reflect.TypeOf((*MockCache)(nil).Set), key, v)
+}
+
 // MockBasicAuthentifier is a mock of BasicAuthentifier interface.
 type MockBasicAuthentifier struct {
 	ctrl     *gomock.Controller